@@ -1,9 +1,17 @@
 package core
 
-import "math"
+import (
+	"math"
+	"sync/atomic"
+)
 
 type Matrix [][]float64
 
+// lazily computed inverse of a transform, safe for concurrent use
+type CachedInverse struct {
+	value atomic.Value
+}
+
 func NewMatrix(rows, cols int) Matrix {
 	matrix := make(Matrix, rows)
 	for i := range matrix {
@@ -182,6 +190,18 @@ func (matrix Matrix) Inverse() Matrix {
 	return result
 }
 
+func (cache *CachedInverse) Get(matrix Matrix) Matrix {
+	if inverse, ok := cache.value.Load().(Matrix); ok {
+		return inverse
+	}
+
+	// concurrent callers may both compute the inverse, but the result is the same
+	inverse := matrix.Inverse()
+	cache.value.Store(inverse)
+
+	return inverse
+}
+
 func (matrix Matrix) Translate(x float64, y float64, z float64) Matrix {
 	translation := NewIdentityMatrix()
 
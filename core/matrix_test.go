@@ -541,3 +541,11 @@ func TestArbitraryViewTransform(t *testing.T) {
 
 	EqualMatrix(t, result, transform)
 }
+
+func TestCachedInverse(t *testing.T) {
+	matrix := NewIdentityMatrix().Translate(5, -3, 2)
+	cache := CachedInverse{}
+
+	EqualMatrix(t, matrix.Inverse(), cache.Get(matrix))
+	EqualMatrix(t, matrix.Inverse(), cache.Get(matrix))
+}
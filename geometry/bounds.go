@@ -0,0 +1,138 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"math"
+)
+
+// axis aligned bounding box
+type Bounds struct {
+	Min Tuple
+	Max Tuple
+}
+
+func NewBounds(min Tuple, max Tuple) Bounds {
+	return Bounds{min, max}
+}
+
+// bounds containing nothing, adding a point makes it contain that point
+func NewEmptyBounds() Bounds {
+	inf := math.Inf(1)
+	return Bounds{NewPoint(inf, inf, inf), NewPoint(-inf, -inf, -inf)}
+}
+
+func NewInfiniteBounds() Bounds {
+	inf := math.Inf(1)
+	return Bounds{NewPoint(-inf, -inf, -inf), NewPoint(inf, inf, inf)}
+}
+
+// bounds of a shape in the space of its parent
+func ParentSpaceBounds(shape Shape) Bounds {
+	return shape.Bounds().Transform(shape.GetTransform())
+}
+
+func (bounds Bounds) IsEmpty() bool {
+	return bounds.Min.X > bounds.Max.X ||
+		bounds.Min.Y > bounds.Max.Y ||
+		bounds.Min.Z > bounds.Max.Z
+}
+
+func (bounds Bounds) IsFinite() bool {
+	for _, value := range []float64{bounds.Min.X, bounds.Min.Y, bounds.Min.Z,
+		bounds.Max.X, bounds.Max.Y, bounds.Max.Z} {
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (bounds Bounds) AddPoint(point Tuple) Bounds {
+	return Bounds{
+		NewPoint(math.Min(bounds.Min.X, point.X), math.Min(bounds.Min.Y, point.Y), math.Min(bounds.Min.Z, point.Z)),
+		NewPoint(math.Max(bounds.Max.X, point.X), math.Max(bounds.Max.Y, point.Y), math.Max(bounds.Max.Z, point.Z))}
+}
+
+func (bounds Bounds) Merge(other Bounds) Bounds {
+	if other.IsEmpty() {
+		return bounds
+	}
+
+	return bounds.AddPoint(other.Min).AddPoint(other.Max)
+}
+
+func (bounds Bounds) ContainsPoint(point Tuple) bool {
+	return bounds.Min.X <= point.X && point.X <= bounds.Max.X &&
+		bounds.Min.Y <= point.Y && point.Y <= bounds.Max.Y &&
+		bounds.Min.Z <= point.Z && point.Z <= bounds.Max.Z
+}
+
+// bounds of the eight transformed corners, infinite when the bounds aren't finite
+func (bounds Bounds) Transform(matrix Matrix) Bounds {
+	if bounds.IsEmpty() {
+		return bounds
+	}
+
+	if !bounds.IsFinite() {
+		return NewInfiniteBounds()
+	}
+
+	result := NewEmptyBounds()
+
+	for _, x := range []float64{bounds.Min.X, bounds.Max.X} {
+		for _, y := range []float64{bounds.Min.Y, bounds.Max.Y} {
+			for _, z := range []float64{bounds.Min.Z, bounds.Max.Z} {
+				result = result.AddPoint(matrix.MultiplyTuple(NewPoint(x, y, z)))
+			}
+		}
+	}
+
+	return result
+}
+
+func (bounds Bounds) Centroid() Tuple {
+	return NewPoint((bounds.Min.X+bounds.Max.X)/2,
+		(bounds.Min.Y+bounds.Max.Y)/2,
+		(bounds.Min.Z+bounds.Max.Z)/2)
+}
+
+func (bounds Bounds) SurfaceArea() float64 {
+	if bounds.IsEmpty() {
+		return 0
+	}
+
+	size := bounds.Max.Subtract(bounds.Min)
+	return 2 * (size.X*size.Y + size.Y*size.Z + size.Z*size.X)
+}
+
+// slab test, rays starting inside the bounds always hit
+func (bounds Bounds) Intersects(ray Ray) bool {
+	xtMin, xtMax := checkSlab(ray.Origin.X, ray.Direction.X, bounds.Min.X, bounds.Max.X)
+	ytMin, ytMax := checkSlab(ray.Origin.Y, ray.Direction.Y, bounds.Min.Y, bounds.Max.Y)
+	ztMin, ztMax := checkSlab(ray.Origin.Z, ray.Direction.Z, bounds.Min.Z, bounds.Max.Z)
+
+	tMin := math.Max(math.Max(xtMin, ytMin), ztMin)
+	tMax := math.Min(math.Min(xtMax, ytMax), ztMax)
+
+	return tMin <= tMax && tMax >= 0
+}
+
+func checkSlab(origin float64, direction float64, min float64, max float64) (float64, float64) {
+	if math.Abs(direction) < Epsilon {
+		if origin < min || origin > max {
+			return math.Inf(1), math.Inf(-1)
+		}
+
+		return math.Inf(-1), math.Inf(1)
+	}
+
+	tmin := (min - origin) / direction
+	tmax := (max - origin) / direction
+
+	if tmin > tmax {
+		return tmax, tmin
+	}
+
+	return tmin, tmax
+}
@@ -0,0 +1,105 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmptyBounds(t *testing.T) {
+	bounds := NewEmptyBounds()
+
+	assert.True(t, bounds.IsEmpty())
+	assert.Equal(t, 0.0, bounds.SurfaceArea())
+}
+
+func TestAddPointsToBounds(t *testing.T) {
+	bounds := NewEmptyBounds().AddPoint(NewPoint(-5, 2, 0)).AddPoint(NewPoint(7, 0, -3))
+
+	assert.Equal(t, NewPoint(-5, 0, -3), bounds.Min)
+	assert.Equal(t, NewPoint(7, 2, 0), bounds.Max)
+}
+
+func TestMergeBounds(t *testing.T) {
+	a := NewBounds(NewPoint(-5, -2, 0), NewPoint(7, 4, 4))
+	b := NewBounds(NewPoint(8, -7, -2), NewPoint(14, 2, 8))
+
+	merged := a.Merge(b)
+
+	assert.Equal(t, NewPoint(-5, -7, -2), merged.Min)
+	assert.Equal(t, NewPoint(14, 4, 8), merged.Max)
+	assert.Equal(t, a, a.Merge(NewEmptyBounds()))
+}
+
+func TestShapeBounds(t *testing.T) {
+	cylinder := NewCylinder()
+	cylinder.Minimum = -5
+	cylinder.Maximum = 3
+	cone := NewCone()
+	cone.Minimum = -5
+	cone.Maximum = 3
+	triangle := NewTriangle(NewPoint(-3, 7, 2), NewPoint(6, 2, -4), NewPoint(2, -1, -1))
+
+	assert.Equal(t, NewBounds(NewPoint(-1, -1, -1), NewPoint(1, 1, 1)), NewSphere().Bounds())
+	assert.Equal(t, NewBounds(NewPoint(-1, -1, -1), NewPoint(1, 1, 1)), NewCube().Bounds())
+	assert.Equal(t, NewBounds(NewPoint(-1, -5, -1), NewPoint(1, 3, 1)), cylinder.Bounds())
+	assert.Equal(t, NewBounds(NewPoint(-5, -5, -5), NewPoint(5, 3, 5)), cone.Bounds())
+	assert.Equal(t, NewBounds(NewPoint(-3, -1, -4), NewPoint(6, 7, 2)), triangle.Bounds())
+	assert.False(t, NewPlane().Bounds().IsFinite())
+}
+
+func TestTransformBounds(t *testing.T) {
+	bounds := NewBounds(NewPoint(-1, -1, -1), NewPoint(1, 1, 1))
+	matrix := NewIdentityMatrix().RotateX(math.Pi / 4).RotateY(math.Pi / 4)
+
+	transformed := bounds.Transform(matrix)
+
+	EqualTuple(t, NewPoint(-1.41421, -1.70711, -1.70711), transformed.Min)
+	EqualTuple(t, NewPoint(1.41421, 1.70711, 1.70711), transformed.Max)
+	assert.False(t, NewPlane().Bounds().Transform(matrix).IsFinite())
+}
+
+func TestGroupBounds(t *testing.T) {
+	sphere := NewSphere()
+	sphere.Transform = sphere.Transform.Translate(2, 5, -3).Scale(2, 2, 2)
+	cylinder := NewCylinder()
+	cylinder.Minimum = -2
+	cylinder.Maximum = 2
+	cylinder.Transform = cylinder.Transform.Translate(-4, -1, 4).Scale(0.5, 1, 0.5)
+	group := NewGroup()
+	group.AddChild(sphere, cylinder)
+
+	bounds := group.Bounds()
+
+	EqualTuple(t, NewPoint(-4.5, -3, -5), bounds.Min)
+	EqualTuple(t, NewPoint(4, 7, 4.5), bounds.Max)
+}
+
+func TestRayIntersectsBounds(t *testing.T) {
+	examples := []struct {
+		origin    Tuple
+		direction Tuple
+		expected  bool
+	}{
+		{NewPoint(15, 1, 2), NewVector(-1, 0, 0), true},
+		{NewPoint(-5, -1, 4), NewVector(1, 0, 0), true},
+		{NewPoint(7, 6, 5), NewVector(0, -1, 0), true},
+		{NewPoint(7, 5, 1.5), NewVector(0, 0, 1), false},
+		{NewPoint(8, 3.5, 7), NewVector(0, 0, -1), true},
+		{NewPoint(7, 4, 6), NewVector(0, 0, 1), true},
+		{NewPoint(9, -1, -8), NewVector(2, 4, 6), false},
+		{NewPoint(8, 3, -4), NewVector(6, 2, 4), false},
+		{NewPoint(18, 3, 3), NewVector(-1, 0, 0), true},
+		{NewPoint(-5, 3, 3), NewVector(-1, 0, 0), false},
+	}
+
+	bounds := NewBounds(NewPoint(5, -2, 0), NewPoint(11, 4, 7))
+
+	for _, example := range examples {
+		ray := NewRay(example.origin, example.direction.Normalize())
+
+		assert.Equal(t, example.expected, bounds.Intersects(ray))
+	}
+}
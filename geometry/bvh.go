@@ -0,0 +1,194 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"sort"
+)
+
+type BVHSplit int
+
+const (
+	// split where the surface area heuristic estimates the cheapest traversal
+	SplitSAH BVHSplit = iota
+	// split at the median centroid along the longest axis
+	SplitMedian
+)
+
+const maxShapesPerLeaf = 4
+
+// bounding volume hierarchy over shapes, in the space of their parent
+type BVH struct {
+	root *bvhNode
+	// shapes without finite bounds, such as planes, are always tested
+	unbounded []Shape
+}
+
+type bvhNode struct {
+	bounds Bounds
+	left   *bvhNode
+	right  *bvhNode
+	shapes []Shape
+}
+
+type bvhEntry struct {
+	shape    Shape
+	bounds   Bounds
+	centroid Tuple
+}
+
+func NewBVH(shapes []Shape, split BVHSplit) *BVH {
+	bvh := BVH{}
+	entries := []bvhEntry{}
+
+	for _, shape := range shapes {
+		bounds := ParentSpaceBounds(shape)
+
+		if bounds.IsFinite() {
+			entries = append(entries, bvhEntry{shape, bounds, bounds.Centroid()})
+		} else {
+			bvh.unbounded = append(bvh.unbounded, shape)
+		}
+	}
+
+	if len(entries) > 0 {
+		bvh.root = buildBVHNode(entries, split)
+	}
+
+	return &bvh
+}
+
+// intersections in no particular order
+func (bvh *BVH) Intersects(ray Ray) []Intersection {
+	xs := []Intersection{}
+
+	for _, shape := range bvh.unbounded {
+		xs = append(xs, shape.Intersects(ray)...)
+	}
+
+	if bvh.root != nil {
+		xs = bvh.root.intersects(ray, xs)
+	}
+
+	return xs
+}
+
+func (node *bvhNode) intersects(ray Ray, xs []Intersection) []Intersection {
+	if !node.bounds.Intersects(ray) {
+		return xs
+	}
+
+	for _, shape := range node.shapes {
+		xs = append(xs, shape.Intersects(ray)...)
+	}
+
+	if node.left != nil {
+		xs = node.left.intersects(ray, xs)
+		xs = node.right.intersects(ray, xs)
+	}
+
+	return xs
+}
+
+func buildBVHNode(entries []bvhEntry, split BVHSplit) *bvhNode {
+	node := bvhNode{bounds: NewEmptyBounds()}
+	centroids := NewEmptyBounds()
+
+	for _, entry := range entries {
+		node.bounds = node.bounds.Merge(entry.bounds)
+		centroids = centroids.AddPoint(entry.centroid)
+	}
+
+	if len(entries) <= maxShapesPerLeaf {
+		node.shapes = leafShapes(entries)
+		return &node
+	}
+
+	axis := longestAxis(centroids)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return component(entries[i].centroid, axis) < component(entries[j].centroid, axis)
+	})
+
+	var mid int
+	if split == SplitMedian {
+		mid = len(entries) / 2
+	} else {
+		mid = sahSplit(entries, node.bounds)
+	}
+
+	if mid <= 0 || mid >= len(entries) {
+		node.shapes = leafShapes(entries)
+		return &node
+	}
+
+	node.left = buildBVHNode(entries[:mid], split)
+	node.right = buildBVHNode(entries[mid:], split)
+
+	return &node
+}
+
+// index splitting the sorted entries with the lowest estimated cost,
+// or 0 when keeping every entry in one leaf is cheaper
+func sahSplit(entries []bvhEntry, bounds Bounds) int {
+	count := len(entries)
+
+	// surface areas of the bounds of entries[i:]
+	rightAreas := make([]float64, count)
+	right := NewEmptyBounds()
+	for i := count - 1; i > 0; i-- {
+		right = right.Merge(entries[i].bounds)
+		rightAreas[i] = right.SurfaceArea()
+	}
+
+	best := 0
+	bestCost := float64(count) * bounds.SurfaceArea()
+	left := NewEmptyBounds()
+
+	for i := 1; i < count; i++ {
+		left = left.Merge(entries[i-1].bounds)
+		cost := left.SurfaceArea()*float64(i) + rightAreas[i]*float64(count-i)
+
+		if cost < bestCost {
+			best = i
+			bestCost = cost
+		}
+	}
+
+	// the node is too large to be a leaf even if no split helps
+	if best == 0 && count > 2*maxShapesPerLeaf {
+		return count / 2
+	}
+
+	return best
+}
+
+func leafShapes(entries []bvhEntry) []Shape {
+	shapes := make([]Shape, len(entries))
+	for i, entry := range entries {
+		shapes[i] = entry.shape
+	}
+
+	return shapes
+}
+
+func longestAxis(bounds Bounds) int {
+	size := bounds.Max.Subtract(bounds.Min)
+
+	if size.X >= size.Y && size.X >= size.Z {
+		return 0
+	} else if size.Y >= size.Z {
+		return 1
+	}
+
+	return 2
+}
+
+func component(tuple Tuple, axis int) float64 {
+	switch axis {
+	case 0:
+		return tuple.X
+	case 1:
+		return tuple.Y
+	default:
+		return tuple.Z
+	}
+}
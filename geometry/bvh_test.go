@@ -0,0 +1,92 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sortedTs(xs []Intersection) []float64 {
+	ts := make([]float64, len(xs))
+	for i, x := range xs {
+		ts[i] = x.T
+	}
+
+	sort.Float64s(ts)
+	return ts
+}
+
+func gridOfSpheres() []Shape {
+	shapes := []Shape{}
+
+	for x := -5; x <= 5; x++ {
+		for z := -5; z <= 5; z++ {
+			sphere := NewSphere()
+			sphere.Transform = sphere.Transform.Translate(float64(x)*3, 0, float64(z)*3)
+			shapes = append(shapes, sphere)
+		}
+	}
+
+	return shapes
+}
+
+func TestBVHSplitsIntoTree(t *testing.T) {
+	shapes := gridOfSpheres()
+
+	for _, split := range []BVHSplit{SplitSAH, SplitMedian} {
+		bvh := NewBVH(shapes, split)
+
+		assert.NotNil(t, bvh.root.left)
+		assert.NotNil(t, bvh.root.right)
+		assert.Equal(t, 0, len(bvh.root.shapes))
+	}
+}
+
+func TestBVHMatchesBruteForce(t *testing.T) {
+	shapes := append(gridOfSpheres(), NewPlane())
+	rays := []Ray{
+		NewRay(NewPoint(0, 0, -20), NewVector(0, 0, 1)),
+		NewRay(NewPoint(3, 0, -20), NewVector(0, 0, 1)),
+		NewRay(NewPoint(-20, 0.5, 6), NewVector(1, 0, 0)),
+		NewRay(NewPoint(-20, 5, -20), NewVector(1, -0.2, 1).Normalize()),
+		NewRay(NewPoint(1.5, 0, 1.5), NewVector(0, 1, 0)),
+	}
+
+	for _, split := range []BVHSplit{SplitSAH, SplitMedian} {
+		bvh := NewBVH(shapes, split)
+
+		for _, ray := range rays {
+			expected := []Intersection{}
+			for _, shape := range shapes {
+				expected = append(expected, shape.Intersects(ray)...)
+			}
+
+			assert.Equal(t, sortedTs(expected), sortedTs(bvh.Intersects(ray)))
+		}
+	}
+}
+
+func TestBVHKeepsUnboundedShapes(t *testing.T) {
+	plane := NewPlane()
+	bvh := NewBVH([]Shape{plane, NewSphere()}, SplitSAH)
+
+	assert.Equal(t, []Shape{plane}, bvh.unbounded)
+}
+
+func TestGroupBVH(t *testing.T) {
+	group := NewGroup()
+	group.AddChild(gridOfSpheres()...)
+	group.Transform = group.Transform.Scale(2, 2, 2)
+	ray := NewRay(NewPoint(6, 0, -50), NewVector(0, 0, 1))
+	expected := group.Intersects(ray)
+
+	group.BuildBVH(SplitSAH)
+
+	assert.NotNil(t, group.bvh)
+	assert.Equal(t, expected, group.Intersects(ray))
+
+	group.AddChild(NewSphere())
+	assert.Nil(t, group.bvh)
+}
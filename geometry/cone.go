@@ -0,0 +1,85 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"math"
+)
+
+// double napped cone around the y axis with its apex at the origin,
+// truncated at Minimum and Maximum and capped at both ends when Closed
+type Cone struct {
+	Minimum float64
+	Maximum float64
+	Closed  bool
+	ShapeImpl
+}
+
+func NewCone() *Cone {
+	return &Cone{math.Inf(-1), math.Inf(1), false, NewShapeImpl()}
+}
+
+func (cone *Cone) Intersects(ray Ray) []Intersection {
+	xs := []Intersection{}
+
+	ray = ray.Transform(cone.GetInverse())
+
+	a := math.Pow(ray.Direction.X, 2) - math.Pow(ray.Direction.Y, 2) + math.Pow(ray.Direction.Z, 2)
+	b := 2*ray.Origin.X*ray.Direction.X - 2*ray.Origin.Y*ray.Direction.Y + 2*ray.Origin.Z*ray.Direction.Z
+	c := math.Pow(ray.Origin.X, 2) - math.Pow(ray.Origin.Y, 2) + math.Pow(ray.Origin.Z, 2)
+
+	if math.Abs(a) < Epsilon {
+		// ray is parallel to one of the cone's halves
+		if math.Abs(b) >= Epsilon {
+			t := -c / (2 * b)
+			xs = appendWithinBounds(xs, ray, t, cone.Minimum, cone.Maximum, cone)
+		}
+	} else {
+		discriminant := b*b - 4*a*c
+
+		if discriminant >= 0 {
+			t0 := (-b - math.Sqrt(discriminant)) / (2 * a)
+			t1 := (-b + math.Sqrt(discriminant)) / (2 * a)
+
+			if t0 > t1 {
+				t0, t1 = t1, t0
+			}
+
+			xs = appendWithinBounds(xs, ray, t0, cone.Minimum, cone.Maximum, cone)
+			xs = appendWithinBounds(xs, ray, t1, cone.Minimum, cone.Maximum, cone)
+		}
+	}
+
+	if cone.Closed {
+		xs = appendCaps(xs, ray, cone.Minimum, cone.Maximum,
+			math.Abs(cone.Minimum), math.Abs(cone.Maximum), cone)
+	}
+
+	return xs
+}
+
+func (cone *Cone) NormalAt(point Tuple, hit Intersection) Tuple {
+	objectPoint := WorldToObject(cone, point)
+	var objectNormal Tuple
+
+	distance := math.Pow(objectPoint.X, 2) + math.Pow(objectPoint.Z, 2)
+
+	if distance < math.Pow(cone.Maximum, 2) && objectPoint.Y >= cone.Maximum-Epsilon {
+		objectNormal = NewVector(0, 1, 0)
+	} else if distance < math.Pow(cone.Minimum, 2) && objectPoint.Y <= cone.Minimum+Epsilon {
+		objectNormal = NewVector(0, -1, 0)
+	} else {
+		y := math.Sqrt(distance)
+		if objectPoint.Y > 0 {
+			y = -y
+		}
+
+		objectNormal = NewVector(objectPoint.X, y, objectPoint.Z)
+	}
+
+	return NormalToWorld(cone, objectNormal)
+}
+
+func (cone *Cone) Bounds() Bounds {
+	limit := math.Max(math.Abs(cone.Minimum), math.Abs(cone.Maximum))
+	return NewBounds(NewPoint(-limit, cone.Minimum, -limit), NewPoint(limit, cone.Maximum, limit))
+}
@@ -0,0 +1,89 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRayIntersectsCone(t *testing.T) {
+	examples := [][]Tuple{
+		{NewPoint(0, 0, -5), NewVector(0, 0, 1)},
+		{NewPoint(0, 0, -5), NewVector(1, 1, 1)},
+		{NewPoint(1, 1, -5), NewVector(-0.5, -1, 1)},
+	}
+
+	expected := [][]float64{
+		{5, 5},
+		{8.66025, 8.66025},
+		{4.55006, 49.44994},
+	}
+
+	c := NewCone()
+
+	for i := range examples {
+		r := NewRay(examples[i][0], examples[i][1].Normalize())
+		xs := c.Intersects(r)
+
+		assert.Equal(t, 2, len(xs))
+		assert.InDelta(t, expected[i][0], xs[0].T, 0.0001)
+		assert.InDelta(t, expected[i][1], xs[1].T, 0.0001)
+	}
+}
+
+func TestRayParallelToConeHalf(t *testing.T) {
+	c := NewCone()
+	r := NewRay(NewPoint(0, 0, -1), NewVector(0, 1, 1).Normalize())
+
+	xs := c.Intersects(r)
+
+	assert.Equal(t, 1, len(xs))
+	assert.InDelta(t, 0.35355, xs[0].T, Epsilon)
+}
+
+func TestIntersectingConeEndCaps(t *testing.T) {
+	examples := [][]Tuple{
+		{NewPoint(0, 0, -5), NewVector(0, 1, 0)},
+		{NewPoint(0, 0, -0.25), NewVector(0, 1, 1)},
+		{NewPoint(0, 0, -0.25), NewVector(0, 1, 0)},
+	}
+
+	expected := []int{0, 2, 4}
+
+	c := NewCone()
+	c.Minimum = -0.5
+	c.Maximum = 0.5
+	c.Closed = true
+
+	for i := range examples {
+		r := NewRay(examples[i][0], examples[i][1].Normalize())
+		xs := c.Intersects(r)
+
+		assert.Equal(t, expected[i], len(xs))
+	}
+}
+
+func TestNormalOnSurfaceOfCone(t *testing.T) {
+	examples := [][]Tuple{
+		{NewPoint(1, 1, 1), NewVector(1, -math.Sqrt2, 1).Normalize()},
+		{NewPoint(-1, -1, 0), NewVector(-1, 1, 0).Normalize()},
+	}
+
+	c := NewCone()
+
+	for i := range examples {
+		EqualTuple(t, examples[i][1], c.NormalAt(examples[i][0], Intersection{}))
+	}
+}
+
+func TestNormalOnConeEndCaps(t *testing.T) {
+	c := NewCone()
+	c.Minimum = -1
+	c.Maximum = 2
+	c.Closed = true
+
+	EqualTuple(t, NewVector(0, 1, 0), c.NormalAt(NewPoint(0.5, 2, 0.5), Intersection{}))
+	EqualTuple(t, NewVector(0, -1, 0), c.NormalAt(NewPoint(0.5, -1, 0), Intersection{}))
+}
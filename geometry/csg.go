@@ -0,0 +1,128 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"sort"
+)
+
+type CSGOperation int
+
+const (
+	CSGUnion CSGOperation = iota
+	CSGIntersection
+	CSGDifference
+)
+
+// constructive solid geometry combining two shapes, which may themselves be
+// groups or other CSG shapes
+type CSG struct {
+	Operation CSGOperation
+	Left      Shape
+	Right     Shape
+	ShapeImpl
+}
+
+func NewCSG(operation CSGOperation, left Shape, right Shape) *CSG {
+	csg := CSG{operation, left, right, NewShapeImpl()}
+	left.SetParent(&csg)
+	right.SetParent(&csg)
+
+	return &csg
+}
+
+func (csg *CSG) Intersects(ray Ray) []Intersection {
+	ray = ray.Transform(csg.GetInverse())
+
+	xs := append(csg.Left.Intersects(ray), csg.Right.Intersects(ray)...)
+
+	sort.Slice(xs, func(i, j int) bool {
+		return xs[i].T < xs[j].T
+	})
+
+	return csg.FilterIntersections(xs)
+}
+
+// keeps the sorted intersections that lie on the surface of the combined shape
+func (csg *CSG) FilterIntersections(xs []Intersection) []Intersection {
+	result := []Intersection{}
+
+	// whether the ray is currently inside the left or right shape
+	inLeft := false
+	inRight := false
+
+	for _, x := range xs {
+		leftHit := includes(csg.Left, x.Object)
+
+		if IntersectionAllowed(csg.Operation, leftHit, inLeft, inRight) {
+			result = append(result, x)
+		}
+
+		if leftHit {
+			inLeft = !inLeft
+		} else {
+			inRight = !inRight
+		}
+	}
+
+	return result
+}
+
+func IntersectionAllowed(operation CSGOperation, leftHit bool, inLeft bool, inRight bool) bool {
+	switch operation {
+	case CSGUnion:
+		return (leftHit && !inRight) || (!leftHit && !inLeft)
+	case CSGIntersection:
+		return (leftHit && inRight) || (!leftHit && inLeft)
+	case CSGDifference:
+		return (leftHit && !inRight) || (!leftHit && inLeft)
+	}
+
+	return false
+}
+
+func (csg *CSG) NormalAt(point Tuple, hit Intersection) Tuple {
+	panic("precondition - CSG shapes have no surface, use the normal of the child that was hit")
+}
+
+func (csg *CSG) Bounds() Bounds {
+	return ParentSpaceBounds(csg.Left).Merge(ParentSpaceBounds(csg.Right))
+}
+
+// sets the material of the CSG shape and both of its children
+func (csg *CSG) SetMaterial(material Material) {
+	csg.Material = material
+	csg.Left.SetMaterial(material)
+	csg.Right.SetMaterial(material)
+}
+
+// the solid a surface belongs to, the outermost CSG shape containing it or
+// the shape itself
+func SolidOf(shape Shape) Shape {
+	solid := shape
+
+	for parent := shape.GetParent(); parent != nil; parent = parent.GetParent() {
+		if _, ok := parent.(*CSG); ok {
+			solid = parent
+		}
+	}
+
+	return solid
+}
+
+// whether target is shape or one of its descendants
+func includes(shape Shape, target Shape) bool {
+	switch shape := shape.(type) {
+	case *Group:
+		for _, child := range shape.Children {
+			if includes(child, target) {
+				return true
+			}
+		}
+
+		return false
+	case *CSG:
+		return includes(shape.Left, target) || includes(shape.Right, target)
+	}
+
+	return shape == target
+}
@@ -0,0 +1,163 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCSG(t *testing.T) {
+	s1 := NewSphere()
+	s2 := NewCube()
+
+	csg := NewCSG(CSGUnion, s1, s2)
+
+	assert.Equal(t, CSGUnion, csg.Operation)
+	assert.Equal(t, s1, csg.Left)
+	assert.Equal(t, s2, csg.Right)
+	assert.Equal(t, csg, s1.GetParent())
+	assert.Equal(t, csg, s2.GetParent())
+}
+
+func TestCSGIntersectionAllowed(t *testing.T) {
+	examples := []struct {
+		operation CSGOperation
+		leftHit   bool
+		inLeft    bool
+		inRight   bool
+		expected  bool
+	}{
+		{CSGUnion, true, true, true, false},
+		{CSGUnion, true, true, false, true},
+		{CSGUnion, true, false, true, false},
+		{CSGUnion, true, false, false, true},
+		{CSGUnion, false, true, true, false},
+		{CSGUnion, false, true, false, false},
+		{CSGUnion, false, false, true, true},
+		{CSGUnion, false, false, false, true},
+		{CSGIntersection, true, true, true, true},
+		{CSGIntersection, true, true, false, false},
+		{CSGIntersection, true, false, true, true},
+		{CSGIntersection, true, false, false, false},
+		{CSGIntersection, false, true, true, true},
+		{CSGIntersection, false, true, false, true},
+		{CSGIntersection, false, false, true, false},
+		{CSGIntersection, false, false, false, false},
+		{CSGDifference, true, true, true, false},
+		{CSGDifference, true, true, false, true},
+		{CSGDifference, true, false, true, false},
+		{CSGDifference, true, false, false, true},
+		{CSGDifference, false, true, true, true},
+		{CSGDifference, false, true, false, true},
+		{CSGDifference, false, false, true, false},
+		{CSGDifference, false, false, false, false},
+	}
+
+	for _, example := range examples {
+		result := IntersectionAllowed(example.operation, example.leftHit, example.inLeft, example.inRight)
+
+		assert.Equal(t, example.expected, result)
+	}
+}
+
+func TestCSGFilterIntersections(t *testing.T) {
+	examples := []struct {
+		operation CSGOperation
+		x0        int
+		x1        int
+	}{
+		{CSGUnion, 0, 3},
+		{CSGIntersection, 1, 2},
+		{CSGDifference, 0, 1},
+	}
+
+	for _, example := range examples {
+		s1 := NewSphere()
+		s2 := NewCube()
+		csg := NewCSG(example.operation, s1, s2)
+		xs := []Intersection{
+			NewIntersection(1, s1),
+			NewIntersection(2, s2),
+			NewIntersection(3, s1),
+			NewIntersection(4, s2)}
+
+		result := csg.FilterIntersections(xs)
+
+		assert.Equal(t, 2, len(result))
+		assert.Equal(t, xs[example.x0], result[0])
+		assert.Equal(t, xs[example.x1], result[1])
+	}
+}
+
+func TestRayMissesCSG(t *testing.T) {
+	csg := NewCSG(CSGUnion, NewSphere(), NewCube())
+	ray := NewRay(NewPoint(0, 2, -5), NewVector(0, 0, 1))
+
+	xs := csg.Intersects(ray)
+
+	assert.Equal(t, 0, len(xs))
+}
+
+func TestRayHitsCSG(t *testing.T) {
+	s1 := NewSphere()
+	s2 := NewSphere()
+	s2.Transform = s2.Transform.Translate(0, 0, 0.5)
+	csg := NewCSG(CSGUnion, s1, s2)
+	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+
+	xs := csg.Intersects(ray)
+
+	assert.Equal(t, 2, len(xs))
+	assert.Equal(t, 4.0, xs[0].T)
+	assert.Equal(t, s1, xs[0].Object)
+	assert.Equal(t, 6.5, xs[1].T)
+	assert.Equal(t, s2, xs[1].Object)
+}
+
+func TestNestedCSGFiltersByDescendants(t *testing.T) {
+	s1 := NewSphere()
+	s2 := NewSphere()
+	s2.Transform = s2.Transform.Translate(0, 0, 0.5)
+	cube := NewCube()
+	cube.Transform = cube.Transform.Translate(0, 0, 1).Scale(2, 2, 1)
+	group := NewGroup()
+	group.AddChild(NewCSG(CSGUnion, s1, s2))
+	csg := NewCSG(CSGDifference, group, cube)
+	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+
+	xs := csg.Intersects(ray)
+
+	// the cube removes the far side of the spheres
+	assert.Equal(t, 2, len(xs))
+	assert.Equal(t, s1, xs[0].Object)
+	assert.Equal(t, 4.0, xs[0].T)
+	assert.Equal(t, cube, xs[1].Object)
+	assert.Equal(t, 5.0, xs[1].T)
+}
+
+func TestCSGBounds(t *testing.T) {
+	right := NewSphere()
+	right.Transform = right.Transform.Translate(2, 3, 4)
+	csg := NewCSG(CSGDifference, NewSphere(), right)
+
+	bounds := csg.Bounds()
+
+	assert.Equal(t, NewPoint(-1, -1, -1), bounds.Min)
+	assert.Equal(t, NewPoint(3, 4, 5), bounds.Max)
+}
+
+func TestSolidOfShape(t *testing.T) {
+	s1 := NewSphere()
+	s2 := NewSphere()
+	inner := NewCSG(CSGUnion, s1, NewCube())
+	outer := NewCSG(CSGDifference, inner, s2)
+	group := NewGroup()
+	group.AddChild(outer)
+	plain := NewSphere()
+	group.AddChild(plain)
+
+	assert.Equal(t, outer, SolidOf(s1))
+	assert.Equal(t, outer, SolidOf(s2))
+	assert.Equal(t, plain, SolidOf(plain))
+}
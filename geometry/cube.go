@@ -6,18 +6,16 @@ import (
 )
 
 type Cube struct {
-	origin        Tuple
-	Transform     Matrix
-	cachedInverse Matrix
-	Material      Material
+	origin Tuple
+	ShapeImpl
 }
 
 func NewCube() *Cube {
-	return &Cube{NewPoint(0, 0, 0), NewIdentityMatrix(), nil, NewMaterial()}
+	return &Cube{NewPoint(0, 0, 0), NewShapeImpl()}
 }
 
-func (cube *Cube) NormalAt(point Tuple) Tuple {
-	objectPoint := cube.GetInverse().MultiplyTuple(point)
+func (cube *Cube) NormalAt(point Tuple, hit Intersection) Tuple {
+	objectPoint := WorldToObject(cube, point)
 	var objectNormal Tuple
 
 	maxC := math.Max(math.Max(math.Abs(objectPoint.X), math.Abs(objectPoint.Y)), math.Abs(objectPoint.Z))
@@ -30,10 +28,7 @@ func (cube *Cube) NormalAt(point Tuple) Tuple {
 		objectNormal = NewVector(0, 0, objectPoint.Z)
 	}
 
-	worldNormal := cube.GetInverse().Transpose().MultiplyTuple(objectNormal)
-	worldNormal.W = 0
-
-	return worldNormal.Normalize()
+	return NormalToWorld(cube, objectNormal)
 }
 
 func (cube *Cube) Intersects(ray Ray) []Intersection {
@@ -49,27 +44,7 @@ func (cube *Cube) Intersects(ray Ray) []Intersection {
 		return []Intersection{}
 	}
 
-	return []Intersection{{tMin, cube}, {tMax, cube}}
-}
-
-func (cube *Cube) GetMaterial() Material {
-	return cube.Material
-}
-
-func (cube *Cube) SetMaterial(material Material) {
-	cube.Material = material
-}
-
-func (cube *Cube) GetTransform() Matrix {
-	return cube.Transform
-}
-
-func (cube *Cube) GetInverse() Matrix {
-	if cube.cachedInverse == nil {
-		cube.cachedInverse = cube.Transform.Inverse()
-	}
-
-	return cube.cachedInverse
+	return []Intersection{NewIntersection(tMin, cube), NewIntersection(tMax, cube)}
 }
 
 func checkAxis(origin, direction float64) (float64, float64) {
@@ -92,3 +67,58 @@ func checkAxis(origin, direction float64) (float64, float64) {
 		return tmin, tmax
 	}
 }
+
+func (cube *Cube) Bounds() Bounds {
+	return NewBounds(NewPoint(-1, -1, -1), NewPoint(1, 1, 1))
+}
+
+func (cube *Cube) Area() float64 {
+	areas := cube.faceAreas()
+
+	return 2 * (areas[0] + areas[1] + areas[2])
+}
+
+// world space area of one face across each of the x, y and z axes
+func (cube *Cube) faceAreas() [3]float64 {
+	x, y, z := worldAxes(cube)
+
+	return [3]float64{4 * y.Cross(z).Magnitude(), 4 * z.Cross(x).Magnitude(), 4 * x.Cross(y).Magnitude()}
+}
+
+// u picks one of the six faces in proportion to its area and the rest of u
+// and v a point on it
+func (cube *Cube) SurfacePoint(u float64, v float64) (Tuple, Tuple) {
+	areas := cube.faceAreas()
+	target := u * 2 * (areas[0] + areas[1] + areas[2])
+
+	// u of 1 or rounding past the end picks the last face
+	face := 5
+	for i := 0; i < 6; i++ {
+		if target < areas[i/2] {
+			face = i
+			break
+		}
+
+		target -= areas[i/2]
+	}
+
+	a, b := 2*math.Min(math.Max(target/areas[face/2], 0), 1)-1, 2*v-1
+
+	sign := 1.0
+	if face%2 == 1 {
+		sign = -1
+	}
+
+	var point, normal Tuple
+
+	switch face / 2 {
+	case 0:
+		point, normal = NewPoint(sign, a, b), NewVector(sign, 0, 0)
+	case 1:
+		point, normal = NewPoint(a, sign, b), NewVector(0, sign, 0)
+	default:
+		point, normal = NewPoint(a, b, sign), NewVector(0, 0, sign)
+	}
+
+	return ObjectToWorld(cube, point), NormalToWorld(cube, normal)
+}
@@ -75,6 +75,6 @@ func TestNormalOnSurfaceOfCube(t *testing.T) {
 	c := NewCube()
 
 	for i := range examples {
-		assert.Equal(t, examples[i][1], c.NormalAt(examples[i][0]))
+		assert.Equal(t, examples[i][1], c.NormalAt(examples[i][0], Intersection{}))
 	}
 }
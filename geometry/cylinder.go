@@ -0,0 +1,112 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"math"
+)
+
+// unit radius cylinder around the y axis, truncated at Minimum and Maximum
+// (exclusive) and capped at both ends when Closed
+type Cylinder struct {
+	Minimum float64
+	Maximum float64
+	Closed  bool
+	ShapeImpl
+}
+
+func NewCylinder() *Cylinder {
+	return &Cylinder{math.Inf(-1), math.Inf(1), false, NewShapeImpl()}
+}
+
+func (cylinder *Cylinder) Intersects(ray Ray) []Intersection {
+	xs := []Intersection{}
+
+	ray = ray.Transform(cylinder.GetInverse())
+
+	a := math.Pow(ray.Direction.X, 2) + math.Pow(ray.Direction.Z, 2)
+
+	// a ray parallel to the y axis can only hit the caps
+	if math.Abs(a) >= Epsilon {
+		b := 2*ray.Origin.X*ray.Direction.X + 2*ray.Origin.Z*ray.Direction.Z
+		c := math.Pow(ray.Origin.X, 2) + math.Pow(ray.Origin.Z, 2) - 1
+
+		discriminant := b*b - 4*a*c
+
+		// no intersections
+		if discriminant < 0 {
+			return xs
+		}
+
+		t0 := (-b - math.Sqrt(discriminant)) / (2 * a)
+		t1 := (-b + math.Sqrt(discriminant)) / (2 * a)
+
+		xs = appendWithinBounds(xs, ray, t0, cylinder.Minimum, cylinder.Maximum, cylinder)
+		xs = appendWithinBounds(xs, ray, t1, cylinder.Minimum, cylinder.Maximum, cylinder)
+	}
+
+	if cylinder.Closed {
+		xs = appendCaps(xs, ray, cylinder.Minimum, cylinder.Maximum, 1, 1, cylinder)
+	}
+
+	return xs
+}
+
+func (cylinder *Cylinder) NormalAt(point Tuple, hit Intersection) Tuple {
+	objectPoint := WorldToObject(cylinder, point)
+	var objectNormal Tuple
+
+	distance := math.Pow(objectPoint.X, 2) + math.Pow(objectPoint.Z, 2)
+
+	if distance < 1 && objectPoint.Y >= cylinder.Maximum-Epsilon {
+		objectNormal = NewVector(0, 1, 0)
+	} else if distance < 1 && objectPoint.Y <= cylinder.Minimum+Epsilon {
+		objectNormal = NewVector(0, -1, 0)
+	} else {
+		objectNormal = NewVector(objectPoint.X, 0, objectPoint.Z)
+	}
+
+	return NormalToWorld(cylinder, objectNormal)
+}
+
+// appends the intersection at t if it lies strictly between minimum and maximum
+func appendWithinBounds(xs []Intersection, ray Ray, t float64, minimum float64, maximum float64, object Shape) []Intersection {
+	y := ray.Origin.Y + t*ray.Direction.Y
+
+	if minimum < y && y < maximum {
+		xs = append(xs, NewIntersection(t, object))
+	}
+
+	return xs
+}
+
+// appends intersections with the end caps at minimum and maximum, whose
+// radii are minRadius and maxRadius
+func appendCaps(xs []Intersection, ray Ray, minimum float64, maximum float64, minRadius float64, maxRadius float64, object Shape) []Intersection {
+	if math.Abs(ray.Direction.Y) < Epsilon {
+		return xs
+	}
+
+	t := (minimum - ray.Origin.Y) / ray.Direction.Y
+	if checkCap(ray, t, minRadius) {
+		xs = append(xs, NewIntersection(t, object))
+	}
+
+	t = (maximum - ray.Origin.Y) / ray.Direction.Y
+	if checkCap(ray, t, maxRadius) {
+		xs = append(xs, NewIntersection(t, object))
+	}
+
+	return xs
+}
+
+// checks if the intersection at t is within radius of the y axis
+func checkCap(ray Ray, t float64, radius float64) bool {
+	x := ray.Origin.X + t*ray.Direction.X
+	z := ray.Origin.Z + t*ray.Direction.Z
+
+	return x*x+z*z <= radius*radius
+}
+
+func (cylinder *Cylinder) Bounds() Bounds {
+	return NewBounds(NewPoint(-1, cylinder.Minimum, -1), NewPoint(1, cylinder.Maximum, 1))
+}
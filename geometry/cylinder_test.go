@@ -0,0 +1,149 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRayMissesCylinder(t *testing.T) {
+	examples := [][]Tuple{
+		{NewPoint(1, 0, 0), NewVector(0, 1, 0)},
+		{NewPoint(0, 0, 0), NewVector(0, 1, 0)},
+		{NewPoint(0, 0, -5), NewVector(1, 1, 1)},
+	}
+
+	c := NewCylinder()
+
+	for i := range examples {
+		r := NewRay(examples[i][0], examples[i][1].Normalize())
+		xs := c.Intersects(r)
+
+		assert.Equal(t, 0, len(xs))
+	}
+}
+
+func TestRayStrikesCylinder(t *testing.T) {
+	examples := [][]Tuple{
+		{NewPoint(1, 0, -5), NewVector(0, 0, 1)},
+		{NewPoint(0, 0, -5), NewVector(0, 0, 1)},
+		{NewPoint(0.5, 0, -5), NewVector(0.1, 1, 1)},
+	}
+
+	expected := [][]float64{
+		{5, 5},
+		{4, 6},
+		{6.80798, 7.08872},
+	}
+
+	c := NewCylinder()
+
+	for i := range examples {
+		r := NewRay(examples[i][0], examples[i][1].Normalize())
+		xs := c.Intersects(r)
+
+		assert.Equal(t, 2, len(xs))
+		assert.InDelta(t, expected[i][0], xs[0].T, Epsilon)
+		assert.InDelta(t, expected[i][1], xs[1].T, Epsilon)
+	}
+}
+
+func TestNormalOnSurfaceOfCylinder(t *testing.T) {
+	examples := [][]Tuple{
+		{NewPoint(1, 0, 0), NewVector(1, 0, 0)},
+		{NewPoint(0, 5, -1), NewVector(0, 0, -1)},
+		{NewPoint(0, -2, 1), NewVector(0, 0, 1)},
+		{NewPoint(-1, 1, 0), NewVector(-1, 0, 0)},
+	}
+
+	c := NewCylinder()
+
+	for i := range examples {
+		assert.Equal(t, examples[i][1], c.NormalAt(examples[i][0], Intersection{}))
+	}
+}
+
+func TestDefaultCylinderBounds(t *testing.T) {
+	c := NewCylinder()
+
+	assert.Equal(t, math.Inf(-1), c.Minimum)
+	assert.Equal(t, math.Inf(1), c.Maximum)
+	assert.Equal(t, false, c.Closed)
+}
+
+func TestIntersectingTruncatedCylinder(t *testing.T) {
+	examples := [][]Tuple{
+		{NewPoint(0, 1.5, 0), NewVector(0.1, 1, 0)},
+		{NewPoint(0, 3, -5), NewVector(0, 0, 1)},
+		{NewPoint(0, 0, -5), NewVector(0, 0, 1)},
+		{NewPoint(0, 2, -5), NewVector(0, 0, 1)},
+		{NewPoint(0, 1, -5), NewVector(0, 0, 1)},
+		{NewPoint(0, 1.5, -2), NewVector(0, 0, 1)},
+	}
+
+	expected := []int{0, 0, 0, 0, 0, 2}
+
+	c := NewCylinder()
+	c.Minimum = 1
+	c.Maximum = 2
+
+	for i := range examples {
+		r := NewRay(examples[i][0], examples[i][1].Normalize())
+		xs := c.Intersects(r)
+
+		assert.Equal(t, expected[i], len(xs))
+	}
+}
+
+func TestIntersectingCapsOfClosedCylinder(t *testing.T) {
+	examples := [][]Tuple{
+		{NewPoint(0, 3, 0), NewVector(0, -1, 0)},
+		{NewPoint(0, 3, -2), NewVector(0, -1, 2)},
+		{NewPoint(0, 4, -2), NewVector(0, -1, 1)},
+		{NewPoint(0, 0, -2), NewVector(0, 1, 2)},
+		{NewPoint(0, -1, -2), NewVector(0, 1, 1)},
+	}
+
+	c := NewCylinder()
+	c.Minimum = 1
+	c.Maximum = 2
+	c.Closed = true
+
+	for i := range examples {
+		r := NewRay(examples[i][0], examples[i][1].Normalize())
+		xs := c.Intersects(r)
+
+		assert.Equal(t, 2, len(xs))
+	}
+}
+
+func TestNormalOnCylinderEndCaps(t *testing.T) {
+	examples := [][]Tuple{
+		{NewPoint(0, 1, 0), NewVector(0, -1, 0)},
+		{NewPoint(0.5, 1, 0), NewVector(0, -1, 0)},
+		{NewPoint(0, 1, 0.5), NewVector(0, -1, 0)},
+		{NewPoint(0, 2, 0), NewVector(0, 1, 0)},
+		{NewPoint(0.5, 2, 0), NewVector(0, 1, 0)},
+		{NewPoint(0, 2, 0.5), NewVector(0, 1, 0)},
+	}
+
+	c := NewCylinder()
+	c.Minimum = 1
+	c.Maximum = 2
+	c.Closed = true
+
+	for i := range examples {
+		assert.Equal(t, examples[i][1], c.NormalAt(examples[i][0], Intersection{}))
+	}
+}
+
+func TestStripePatternOnCylinder(t *testing.T) {
+	c := NewCylinder()
+	c.Transform = c.Transform.Scale(2, 2, 2)
+	pattern := NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black))
+
+	assert.Equal(t, White, PatternColor(pattern, c, NewPoint(1.5, 0, 0)))
+	assert.Equal(t, Black, PatternColor(pattern, c, NewPoint(2, 0, 0)))
+}
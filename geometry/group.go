@@ -0,0 +1,128 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"sort"
+)
+
+// collection of shapes transformed together, children are positioned
+// relative to the group
+type Group struct {
+	Children []Shape
+	bvh      *BVH
+	ShapeImpl
+}
+
+func NewGroup() *Group {
+	return &Group{[]Shape{}, nil, NewShapeImpl()}
+}
+
+// adding children discards the group's BVH until it is built again
+func (group *Group) AddChild(children ...Shape) {
+	for _, child := range children {
+		child.SetParent(group)
+		group.Children = append(group.Children, child)
+	}
+
+	group.bvh = nil
+}
+
+// builds a BVH over the children of this group and every nested group
+func (group *Group) BuildBVH(split BVHSplit) {
+	for _, child := range group.Children {
+		if childGroup, ok := child.(*Group); ok {
+			childGroup.BuildBVH(split)
+		}
+	}
+
+	group.bvh = NewBVH(group.Children, split)
+}
+
+func (group *Group) Intersects(ray Ray) []Intersection {
+	xs := []Intersection{}
+
+	ray = ray.Transform(group.GetInverse())
+
+	if group.bvh != nil {
+		xs = group.bvh.Intersects(ray)
+	} else {
+		for _, child := range group.Children {
+			xs = append(xs, child.Intersects(ray)...)
+		}
+	}
+
+	sort.Slice(xs, func(i, j int) bool {
+		return xs[i].T < xs[j].T
+	})
+
+	return xs
+}
+
+func (group *Group) NormalAt(point Tuple, hit Intersection) Tuple {
+	panic("precondition - groups have no surface, use the normal of the child that was hit")
+}
+
+func (group *Group) Bounds() Bounds {
+	bounds := NewEmptyBounds()
+
+	for _, child := range group.Children {
+		bounds = bounds.Merge(ParentSpaceBounds(child))
+	}
+
+	return bounds
+}
+
+// sets the material of the group and every shape in it
+func (group *Group) SetMaterial(material Material) {
+	group.Material = material
+
+	for _, child := range group.Children {
+		child.SetMaterial(material)
+	}
+}
+
+// total world space area of the children that have a surface to sample
+func (group *Group) Area() float64 {
+	area := 0.0
+
+	for _, child := range group.Children {
+		if sampler, ok := child.(SurfaceSampler); ok {
+			area += sampler.Area()
+		}
+	}
+
+	return area
+}
+
+// u picks a child in proportion to its area and the rest of u and v a
+// point on it
+func (group *Group) SurfacePoint(u float64, v float64) (Tuple, Tuple) {
+	target := u * group.Area()
+	var last SurfaceSampler
+
+	for _, child := range group.Children {
+		sampler, ok := child.(SurfaceSampler)
+		if !ok {
+			continue
+		}
+
+		area := sampler.Area()
+		if area <= 0 {
+			continue
+		}
+
+		if target < area {
+			return sampler.SurfacePoint(target/area, v)
+		}
+
+		target -= area
+		last = sampler
+	}
+
+	if last == nil {
+		panic("precondition - group has no children with a surface to sample")
+	}
+
+	// u of 1 or rounding past the end
+	return last.SurfacePoint(1, v)
+}
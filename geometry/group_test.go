@@ -0,0 +1,140 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGroup(t *testing.T) {
+	group := NewGroup()
+
+	assert.Equal(t, NewIdentityMatrix(), group.Transform)
+	assert.Equal(t, 0, len(group.Children))
+	assert.Nil(t, group.GetParent())
+}
+
+func TestAddChildToGroup(t *testing.T) {
+	group := NewGroup()
+	shape := NewSphere()
+
+	group.AddChild(shape)
+
+	assert.Equal(t, 1, len(group.Children))
+	assert.Equal(t, shape, group.Children[0])
+	assert.Equal(t, group, shape.GetParent())
+}
+
+func TestIntersectRayWithEmptyGroup(t *testing.T) {
+	group := NewGroup()
+	ray := NewRay(NewPoint(0, 0, 0), NewVector(0, 0, 1))
+
+	xs := group.Intersects(ray)
+
+	assert.Equal(t, 0, len(xs))
+}
+
+func TestIntersectRayWithNonEmptyGroup(t *testing.T) {
+	group := NewGroup()
+	s1 := NewSphere()
+	s2 := NewSphere()
+	s2.Transform = s2.Transform.Translate(0, 0, -3)
+	s3 := NewSphere()
+	s3.Transform = s3.Transform.Translate(5, 0, 0)
+	group.AddChild(s1, s2, s3)
+	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+
+	xs := group.Intersects(ray)
+
+	assert.Equal(t, 4, len(xs))
+	assert.Equal(t, s2, xs[0].Object)
+	assert.Equal(t, s2, xs[1].Object)
+	assert.Equal(t, s1, xs[2].Object)
+	assert.Equal(t, s1, xs[3].Object)
+}
+
+func TestIntersectTransformedGroup(t *testing.T) {
+	group := NewGroup()
+	group.Transform = group.Transform.Scale(2, 2, 2)
+	s := NewSphere()
+	s.Transform = s.Transform.Translate(5, 0, 0)
+	group.AddChild(s)
+	ray := NewRay(NewPoint(10, 0, -10), NewVector(0, 0, 1))
+
+	xs := group.Intersects(ray)
+
+	assert.Equal(t, 2, len(xs))
+}
+
+func TestWorldToObjectThroughGroups(t *testing.T) {
+	g1 := NewGroup()
+	g1.Transform = g1.Transform.RotateY(math.Pi / 2)
+	g2 := NewGroup()
+	g2.Transform = g2.Transform.Scale(2, 2, 2)
+	g1.AddChild(g2)
+	s := NewSphere()
+	s.Transform = s.Transform.Translate(5, 0, 0)
+	g2.AddChild(s)
+
+	point := WorldToObject(s, NewPoint(-2, 0, -10))
+
+	EqualTuple(t, NewPoint(0, 0, -1), point)
+}
+
+func TestNormalToWorldThroughGroups(t *testing.T) {
+	g1 := NewGroup()
+	g1.Transform = g1.Transform.RotateY(math.Pi / 2)
+	g2 := NewGroup()
+	g2.Transform = g2.Transform.Scale(1, 2, 3)
+	g1.AddChild(g2)
+	s := NewSphere()
+	s.Transform = s.Transform.Translate(5, 0, 0)
+	g2.AddChild(s)
+
+	normal := NormalToWorld(s, NewVector(math.Sqrt(3)/3, math.Sqrt(3)/3, math.Sqrt(3)/3))
+
+	EqualTuple(t, NewVector(0.28571, 0.42857, -0.85714), normal)
+}
+
+func TestNormalOnChildObject(t *testing.T) {
+	g1 := NewGroup()
+	g1.Transform = g1.Transform.RotateY(math.Pi / 2)
+	g2 := NewGroup()
+	g2.Transform = g2.Transform.Scale(1, 2, 3)
+	g1.AddChild(g2)
+	s := NewSphere()
+	s.Transform = s.Transform.Translate(5, 0, 0)
+	g2.AddChild(s)
+
+	normal := s.NormalAt(NewPoint(1.7321, 1.1547, -5.5774), Intersection{})
+
+	EqualTuple(t, NewVector(0.2857, 0.42854, -0.85716), normal)
+}
+
+func TestGroupSetMaterialAppliesToChildren(t *testing.T) {
+	group := NewGroup()
+	s := NewSphere()
+	c := NewCube()
+	group.AddChild(s, c)
+	material := NewMaterial()
+	material.Color = Red
+
+	group.SetMaterial(material)
+
+	assert.Equal(t, material, group.GetMaterial())
+	assert.Equal(t, material, s.GetMaterial())
+	assert.Equal(t, material, c.GetMaterial())
+}
+
+func TestPatternColorOnChildOfGroup(t *testing.T) {
+	group := NewGroup()
+	group.Transform = group.Transform.Scale(2, 2, 2)
+	s := NewSphere()
+	group.AddChild(s)
+	pattern := NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black))
+
+	assert.Equal(t, White, PatternColor(pattern, s, NewPoint(1.5, 0, 0)))
+	assert.Equal(t, Black, PatternColor(pattern, s, NewPoint(2.5, 0, 0)))
+}
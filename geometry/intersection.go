@@ -7,10 +7,17 @@ import (
 type Intersection struct {
 	T      float64
 	Object Shape
+	// barycentric coordinates of the hit on a triangle
+	U float64
+	V float64
 }
 
 func NewIntersection(t float64, s Shape) Intersection {
-	return Intersection{t, s}
+	return Intersection{t, s, 0, 0}
+}
+
+func NewIntersectionWithUV(t float64, s Shape, u float64, v float64) Intersection {
+	return Intersection{t, s, u, v}
 }
 
 func Hit(intersections []Intersection) (Intersection, error) {
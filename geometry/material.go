@@ -1,6 +1,9 @@
 package geometry
 
-import . "go-raytracer/core"
+import (
+	. "go-raytracer/core"
+	"math"
+)
 
 type Material struct {
 	Color           Color
@@ -12,8 +15,37 @@ type Material struct {
 	Reflective      float64
 	Transparency    float64
 	RefractiveIndex float64
+	// spreads reflections and refractions over a lobe, 0 keeps them sharp
+	// and 1 is fully blurred
+	Roughness float64
+	// fraction of each channel absorbed per unit of distance light travels
+	// inside the object, scaled by AbsorptionDensity
+	Absorption        Color
+	AbsorptionDensity float64
+	// light given off by the surface, scaled by EmissionStrength
+	Emission         Color
+	EmissionStrength float64
+	// shades the surface with a metallic/roughness model in place of
+	// Color, Pattern, Diffuse, Specular, Shininess and Reflective when set
+	PBR *PBR
 }
 
 func NewMaterial() Material {
-	return Material{White, nil, 0.1, 0.9, 0.9, 200, 0, 0, 1}
+	return Material{White, nil, 0.1, 0.9, 0.9, 200, 0, 0, 1, 0, Black, 1, Black, 1, nil}
+}
+
+// light given off by the surface
+func (material Material) Emitted() Color {
+	return material.Emission.MultiplyScalar(material.EmissionStrength)
+}
+
+// fraction of light left after travelling distance inside the object,
+// falling off exponentially by the Beer–Lambert law
+func (material Material) Transmittance(distance float64) Color {
+	density := material.AbsorptionDensity * distance
+
+	return NewColor(
+		math.Exp(-material.Absorption.Red*density),
+		math.Exp(-material.Absorption.Green*density),
+		math.Exp(-material.Absorption.Blue*density))
 }
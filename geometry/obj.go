@@ -0,0 +1,222 @@
+package geometry
+
+import (
+	"bufio"
+	"fmt"
+	. "go-raytracer/core"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// contents of a Wavefront OBJ file, faces are fan triangulated into the
+// default group or the most recent named group
+type ObjFile struct {
+	Vertices      []Tuple
+	Normals       []Tuple
+	TextureCoords []Tuple
+	DefaultGroup  *Group
+	Groups        map[string]*Group
+	// number of lines with statements that are not supported
+	Ignored    uint
+	groupOrder []string
+}
+
+// vertex of a face, indices are zero based and -1 when missing
+type objFaceVertex struct {
+	vertex  int
+	texture int
+	normal  int
+}
+
+func LoadObjFile(path string) (*ObjFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseObj(file)
+}
+
+func ParseObj(reader io.Reader) (*ObjFile, error) {
+	obj := ObjFile{DefaultGroup: NewGroup(), Groups: map[string]*Group{}}
+	current := obj.DefaultGroup
+
+	scanner := bufio.NewScanner(reader)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		var err error
+
+		switch fields[0] {
+		case "v":
+			var v [4]float64
+			v, err = parseObjNumbers(fields[1:], 3, 4)
+			obj.Vertices = append(obj.Vertices, NewPoint(v[0], v[1], v[2]))
+		case "vn":
+			var n [4]float64
+			n, err = parseObjNumbers(fields[1:], 3, 3)
+			obj.Normals = append(obj.Normals, NewVector(n[0], n[1], n[2]))
+		case "vt":
+			var vt [4]float64
+			vt, err = parseObjNumbers(fields[1:], 1, 3)
+			obj.TextureCoords = append(obj.TextureCoords, NewVector(vt[0], vt[1], vt[2]))
+		case "f":
+			err = obj.parseFace(fields[1:], current)
+		case "g", "o":
+			if len(fields) < 2 {
+				err = fmt.Errorf("missing group name")
+				break
+			}
+
+			name := strings.Join(fields[1:], " ")
+			group, found := obj.Groups[name]
+			if !found {
+				group = NewGroup()
+				obj.Groups[name] = group
+				obj.groupOrder = append(obj.groupOrder, name)
+			}
+			current = group
+		default:
+			obj.Ignored++
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("obj: line %d: %w", line, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("obj: line %d: %w", line+1, err)
+	}
+
+	return &obj, nil
+}
+
+// a single group holding the default group's shapes and every named group
+func (obj *ObjFile) ToGroup() *Group {
+	group := NewGroup()
+	group.AddChild(obj.DefaultGroup.Children...)
+
+	for _, name := range obj.groupOrder {
+		group.AddChild(obj.Groups[name])
+	}
+
+	return group
+}
+
+func (obj *ObjFile) parseFace(fields []string, group *Group) error {
+	if len(fields) < 3 {
+		return fmt.Errorf("face needs at least 3 vertices, got %d", len(fields))
+	}
+
+	vertices := make([]objFaceVertex, len(fields))
+	smooth := true
+
+	for i, field := range fields {
+		vertex, err := obj.parseFaceVertex(field)
+		if err != nil {
+			return err
+		}
+
+		vertices[i] = vertex
+		smooth = smooth && vertex.normal >= 0
+	}
+
+	// fan triangulation around the first vertex
+	for i := 1; i < len(vertices)-1; i++ {
+		a, b, c := vertices[0], vertices[i], vertices[i+1]
+		p1, p2, p3 := obj.Vertices[a.vertex], obj.Vertices[b.vertex], obj.Vertices[c.vertex]
+
+		if smooth {
+			group.AddChild(NewSmoothTriangle(p1, p2, p3,
+				obj.Normals[a.normal], obj.Normals[b.normal], obj.Normals[c.normal]))
+		} else {
+			group.AddChild(NewTriangle(p1, p2, p3))
+		}
+	}
+
+	return nil
+}
+
+// parses v, v/vt, v//vn or v/vt/vn
+func (obj *ObjFile) parseFaceVertex(field string) (objFaceVertex, error) {
+	vertex := objFaceVertex{-1, -1, -1}
+	parts := strings.Split(field, "/")
+
+	if len(parts) > 3 {
+		return vertex, fmt.Errorf("invalid face vertex %q", field)
+	}
+
+	var err error
+
+	vertex.vertex, err = parseObjIndex(parts[0], len(obj.Vertices), "vertex")
+	if err != nil {
+		return vertex, err
+	}
+
+	if len(parts) > 1 && parts[1] != "" {
+		vertex.texture, err = parseObjIndex(parts[1], len(obj.TextureCoords), "texture coordinate")
+		if err != nil {
+			return vertex, err
+		}
+	}
+
+	if len(parts) > 2 && parts[2] != "" {
+		vertex.normal, err = parseObjIndex(parts[2], len(obj.Normals), "normal")
+		if err != nil {
+			return vertex, err
+		}
+	}
+
+	return vertex, nil
+}
+
+// converts a one based or negative relative index to a zero based index
+func parseObjIndex(field string, count int, kind string) (int, error) {
+	index, err := strconv.Atoi(field)
+	if err != nil {
+		return -1, fmt.Errorf("invalid %s index %q", kind, field)
+	}
+
+	if index < 0 {
+		index += count
+	} else {
+		index--
+	}
+
+	if index < 0 || index >= count {
+		return -1, fmt.Errorf("%s index %s out of range, %d defined", kind, field, count)
+	}
+
+	return index, nil
+}
+
+// parses between minCount and maxCount numbers, missing ones are 0
+func parseObjNumbers(fields []string, minCount int, maxCount int) ([4]float64, error) {
+	values := [4]float64{}
+
+	if len(fields) < minCount || len(fields) > maxCount {
+		return values, fmt.Errorf("expected %d to %d numbers, got %d", minCount, maxCount, len(fields))
+	}
+
+	for i, field := range fields {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return values, fmt.Errorf("invalid number %q", field)
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
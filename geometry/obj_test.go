@@ -0,0 +1,199 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjIgnoresUnrecognizedLines(t *testing.T) {
+	gibberish := `There was a young lady named Bright
+who traveled much faster than light.
+She set out one day
+in a relative way,
+and came back the previous night.`
+
+	obj, err := ParseObj(strings.NewReader(gibberish))
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint(5), obj.Ignored)
+}
+
+func TestObjVertexRecords(t *testing.T) {
+	file := `
+v -1 1 0
+v -1.0000 0.5000 0.0000
+v 1 0 0
+v 1 1 0`
+
+	obj, err := ParseObj(strings.NewReader(file))
+
+	assert.Nil(t, err)
+	assert.Equal(t, NewPoint(-1, 1, 0), obj.Vertices[0])
+	assert.Equal(t, NewPoint(-1, 0.5, 0), obj.Vertices[1])
+	assert.Equal(t, NewPoint(1, 0, 0), obj.Vertices[2])
+	assert.Equal(t, NewPoint(1, 1, 0), obj.Vertices[3])
+}
+
+func TestObjTriangleFaces(t *testing.T) {
+	file := `
+v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+
+f 1 2 3
+f 1 3 4`
+
+	obj, err := ParseObj(strings.NewReader(file))
+
+	assert.Nil(t, err)
+	t1 := obj.DefaultGroup.Children[0].(*Triangle)
+	t2 := obj.DefaultGroup.Children[1].(*Triangle)
+	assert.Equal(t, obj.Vertices[0], t1.p1)
+	assert.Equal(t, obj.Vertices[1], t1.p2)
+	assert.Equal(t, obj.Vertices[2], t1.p3)
+	assert.Equal(t, obj.Vertices[0], t2.p1)
+	assert.Equal(t, obj.Vertices[2], t2.p2)
+	assert.Equal(t, obj.Vertices[3], t2.p3)
+}
+
+func TestObjTriangulatesPolygons(t *testing.T) {
+	file := `
+v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+v 0 2 0
+
+f 1 2 3 4 5`
+
+	obj, err := ParseObj(strings.NewReader(file))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(obj.DefaultGroup.Children))
+	t3 := obj.DefaultGroup.Children[2].(*Triangle)
+	assert.Equal(t, obj.Vertices[0], t3.p1)
+	assert.Equal(t, obj.Vertices[3], t3.p2)
+	assert.Equal(t, obj.Vertices[4], t3.p3)
+}
+
+func TestObjNamedGroups(t *testing.T) {
+	file := `
+v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+
+g FirstGroup
+f 1 2 3
+g SecondGroup
+f 1 3 4`
+
+	obj, err := ParseObj(strings.NewReader(file))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(obj.DefaultGroup.Children))
+	assert.Equal(t, 1, len(obj.Groups["FirstGroup"].Children))
+	assert.Equal(t, 1, len(obj.Groups["SecondGroup"].Children))
+
+	group := obj.ToGroup()
+	assert.Equal(t, 2, len(group.Children))
+	assert.Equal(t, obj.Groups["FirstGroup"], group.Children[0])
+	assert.Equal(t, obj.Groups["SecondGroup"], group.Children[1])
+}
+
+func TestObjVertexNormalsAndTextureCoords(t *testing.T) {
+	file := `
+v 0 1 0
+v -1 0 0
+v 1 0 0
+
+vn -1 0 0
+vn 1 2 3
+vn 0 1 0
+
+vt 0.5 1
+vt 0 0
+vt 1 0
+
+f 1//3 2//1 3//2
+f 1/1/3 2/2/1 3/3/2
+f 1/1 2/2 3/3`
+
+	obj, err := ParseObj(strings.NewReader(file))
+
+	assert.Nil(t, err)
+	assert.Equal(t, NewVector(1, 2, 3), obj.Normals[1])
+	assert.Equal(t, NewVector(0.5, 1, 0), obj.TextureCoords[0])
+
+	t1 := obj.DefaultGroup.Children[0].(*SmoothTriangle)
+	t2 := obj.DefaultGroup.Children[1].(*SmoothTriangle)
+	assert.Equal(t, obj.Normals[2], t1.n1)
+	assert.Equal(t, obj.Normals[0], t1.n2)
+	assert.Equal(t, obj.Normals[1], t1.n3)
+	assert.Equal(t, t1.p1, t2.p1)
+	assert.Equal(t, t1.n3, t2.n3)
+	assert.IsType(t, &Triangle{}, obj.DefaultGroup.Children[2])
+}
+
+func TestObjNegativeIndices(t *testing.T) {
+	file := `
+v -1 1 0
+v -1 0 0
+v 1 0 0
+f -3 -2 -1`
+
+	obj, err := ParseObj(strings.NewReader(file))
+
+	assert.Nil(t, err)
+	t1 := obj.DefaultGroup.Children[0].(*Triangle)
+	assert.Equal(t, obj.Vertices[0], t1.p1)
+	assert.Equal(t, obj.Vertices[2], t1.p3)
+}
+
+func TestObjMalformedInput(t *testing.T) {
+	examples := []struct {
+		file     string
+		expected string
+	}{
+		{"v 1 2", "obj: line 1: expected 3 to 4 numbers, got 2"},
+		{"v 1 2 3\nv 1 x 3", `obj: line 2: invalid number "x"`},
+		{"v 1 2 3\nv 1 2 3\n\nf 1 2", "obj: line 4: face needs at least 3 vertices, got 2"},
+		{"v 1 2 3\nv 1 2 3\nf 1 2 3", "obj: line 3: vertex index 3 out of range, 2 defined"},
+		{"v 1 2 3\nf 1 1 a", `obj: line 2: invalid vertex index "a"`},
+		{"v 1 2 3\nf 1//1 1 1", "obj: line 2: normal index 1 out of range, 0 defined"},
+		{"g", "obj: line 1: missing group name"},
+	}
+
+	for _, example := range examples {
+		obj, err := ParseObj(strings.NewReader(example.file))
+
+		assert.Nil(t, obj)
+		assert.EqualError(t, err, example.expected)
+	}
+}
+
+func TestObjToGroupTransformAndMaterial(t *testing.T) {
+	file := `
+v 0 1 0
+v -1 0 0
+v 1 0 0
+f 1 2 3`
+
+	obj, _ := ParseObj(strings.NewReader(file))
+	group := obj.ToGroup()
+	group.Transform = group.Transform.Translate(0, 0, 5)
+	material := NewMaterial()
+	material.Color = Red
+	group.SetMaterial(material)
+
+	ray := NewRay(NewPoint(0, 0.5, -2), NewVector(0, 0, 1))
+	xs := group.Intersects(ray)
+
+	assert.Equal(t, 1, len(xs))
+	assert.Equal(t, 7.0, xs[0].T)
+	assert.Equal(t, Red, xs[0].Object.GetMaterial().Color)
+}
@@ -13,7 +13,7 @@ type Pattern interface {
 
 type PatternImpl struct {
 	Transform     Matrix
-	cachedInverse Matrix
+	cachedInverse CachedInverse
 }
 
 type TestPattern struct {
@@ -50,31 +50,31 @@ type CheckersPattern struct {
 }
 
 func NewTestPattern() *TestPattern {
-	return &TestPattern{PatternImpl{NewIdentityMatrix(), nil}}
+	return &TestPattern{PatternImpl{NewIdentityMatrix(), CachedInverse{}}}
 }
 
 func NewSolidPattern(c Color) *SolidPattern {
-	return &SolidPattern{c, PatternImpl{NewIdentityMatrix(), nil}}
+	return &SolidPattern{c, PatternImpl{NewIdentityMatrix(), CachedInverse{}}}
 }
 
 func NewStripePattern(a Pattern, b Pattern) *StripePattern {
-	return &StripePattern{a, b, PatternImpl{NewIdentityMatrix(), nil}}
+	return &StripePattern{a, b, PatternImpl{NewIdentityMatrix(), CachedInverse{}}}
 }
 
 func NewGradientPattern(a Pattern, b Pattern) *GradientPattern {
-	return &GradientPattern{a, b, PatternImpl{NewIdentityMatrix(), nil}}
+	return &GradientPattern{a, b, PatternImpl{NewIdentityMatrix(), CachedInverse{}}}
 }
 
 func NewRingPattern(a Pattern, b Pattern) *RingPattern {
-	return &RingPattern{a, b, PatternImpl{NewIdentityMatrix(), nil}}
+	return &RingPattern{a, b, PatternImpl{NewIdentityMatrix(), CachedInverse{}}}
 }
 
 func NewCheckersPattern(a Pattern, b Pattern) *CheckersPattern {
-	return &CheckersPattern{a, b, PatternImpl{NewIdentityMatrix(), nil}}
+	return &CheckersPattern{a, b, PatternImpl{NewIdentityMatrix(), CachedInverse{}}}
 }
 
 func PatternColor(pattern Pattern, object Shape, worldPoint Tuple) Color {
-	objectPoint := object.GetInverse().MultiplyTuple(worldPoint)
+	objectPoint := WorldToObject(object, worldPoint)
 	patternPoint := pattern.GetInverse().MultiplyTuple(objectPoint)
 
 	return pattern.ColorAt(patternPoint)
@@ -85,11 +85,7 @@ func (pattern *PatternImpl) GetTransform() Matrix {
 }
 
 func (pattern *PatternImpl) GetInverse() Matrix {
-	if pattern.cachedInverse == nil {
-		pattern.cachedInverse = pattern.Transform.Inverse()
-	}
-
-	return pattern.cachedInverse
+	return pattern.cachedInverse.Get(pattern.Transform)
 }
 
 func (pattern *TestPattern) ColorAt(point Tuple) Color {
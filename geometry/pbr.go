@@ -0,0 +1,69 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+)
+
+// physically based metallic/roughness surface, shaded in place of the
+// Phong terms of a Material. Each parameter may come from a pattern
+// instead, scalar parameters use the average of the pattern's channels
+type PBR struct {
+	BaseColor    Color
+	BaseColorMap Pattern
+	// 0 for dielectrics such as plastic, 1 for metals, which have no diffuse
+	// light and tint their reflections with the base color
+	Metallic    float64
+	MetallicMap Pattern
+	// 0 for a mirror finish up to 1 for a matte surface
+	Roughness    float64
+	RoughnessMap Pattern
+	// reflectance of dielectrics seen head on, 0.5 reflects the usual 4%
+	Specular    float64
+	SpecularMap Pattern
+}
+
+func NewPBR(baseColor Color, metallic float64, roughness float64) *PBR {
+	return &PBR{BaseColor: baseColor, Metallic: metallic, Roughness: roughness, Specular: 0.5}
+}
+
+// PBR parameters at one point on a surface
+type PBRSample struct {
+	BaseColor Color
+	Metallic  float64
+	Roughness float64
+	Specular  float64
+}
+
+// parameters at point on object with the maps applied, clamped to 0 to 1
+func (pbr *PBR) At(object Shape, point Tuple) PBRSample {
+	sample := PBRSample{pbr.BaseColor, pbr.Metallic, pbr.Roughness, pbr.Specular}
+
+	if pbr.BaseColorMap != nil {
+		sample.BaseColor = PatternColor(pbr.BaseColorMap, object, point)
+	}
+
+	sample.Metallic = scalarAt(pbr.MetallicMap, sample.Metallic, object, point)
+	sample.Roughness = scalarAt(pbr.RoughnessMap, sample.Roughness, object, point)
+	sample.Specular = scalarAt(pbr.SpecularMap, sample.Specular, object, point)
+
+	return sample
+}
+
+// value of pattern at point as the average of its channels, or value when
+// there is no pattern
+func scalarAt(pattern Pattern, value float64, object Shape, point Tuple) float64 {
+	if pattern != nil {
+		color := PatternColor(pattern, object, point)
+		value = (color.Red + color.Green + color.Blue) / 3
+	}
+
+	return clamp(value, 0, 1)
+}
+
+// reflectance seen head on, up to 8% for dielectrics and the base color
+// for metals
+func (sample PBRSample) F0() Color {
+	dielectric := White.MultiplyScalar(0.08 * sample.Specular)
+
+	return lerpColor(dielectric, sample.BaseColor, sample.Metallic)
+}
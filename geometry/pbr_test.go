@@ -0,0 +1,42 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPBR(t *testing.T) {
+	pbr := NewPBR(Red, 1, 0.3)
+
+	assert.Equal(t, PBR{BaseColor: Red, Metallic: 1, Roughness: 0.3, Specular: 0.5}, *pbr)
+	assert.Nil(t, NewMaterial().PBR)
+}
+
+func TestPBRAtUsesMaps(t *testing.T) {
+	object := NewSphere()
+	pbr := NewPBR(Red, 0, 0.5)
+	pbr.BaseColorMap = NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black))
+	pbr.MetallicMap = NewSolidPattern(NewColor(1, 0.5, 0))
+	pbr.RoughnessMap = NewSolidPattern(NewColor(2, 2, 2))
+
+	left := pbr.At(object, NewPoint(0.5, 0, 0))
+	right := pbr.At(object, NewPoint(1.5, 0, 0))
+
+	assert.Equal(t, White, left.BaseColor)
+	assert.Equal(t, Black, right.BaseColor)
+	assert.Equal(t, 0.5, left.Metallic)
+	assert.Equal(t, 1.0, left.Roughness)
+	assert.Equal(t, 0.5, left.Specular)
+}
+
+func TestPBRF0(t *testing.T) {
+	dielectric := PBRSample{BaseColor: Red, Metallic: 0, Specular: 0.5}
+	metal := PBRSample{BaseColor: Red, Metallic: 1, Specular: 0.5}
+	half := PBRSample{BaseColor: Red, Metallic: 0.5, Specular: 0.5}
+
+	assert.True(t, NewColor(0.04, 0.04, 0.04).Equals(dielectric.F0()))
+	assert.Equal(t, Red, metal.F0())
+	assert.True(t, NewColor(0.52, 0.02, 0.02).Equals(half.F0()))
+}
@@ -6,18 +6,16 @@ import (
 )
 
 type Plane struct {
-	origin        Tuple
-	Transform     Matrix
-	cachedInverse Matrix
-	Material      Material
+	origin Tuple
+	ShapeImpl
 }
 
 func NewPlane() *Plane {
-	return &Plane{NewPoint(0, 0, 0), NewIdentityMatrix(), nil, NewMaterial()}
+	return &Plane{NewPoint(0, 0, 0), NewShapeImpl()}
 }
 
-func (plane *Plane) NormalAt(point Tuple) Tuple {
-	return NewVector(0, 1, 0)
+func (plane *Plane) NormalAt(point Tuple, hit Intersection) Tuple {
+	return NormalToWorld(plane, NewVector(0, 1, 0))
 }
 
 func (plane *Plane) Intersects(ray Ray) []Intersection {
@@ -34,22 +32,7 @@ func (plane *Plane) Intersects(ray Ray) []Intersection {
 	return xs
 }
 
-func (plane *Plane) GetMaterial() Material {
-	return plane.Material
-}
-
-func (plane *Plane) SetMaterial(material Material) {
-	plane.Material = material
-}
-
-func (plane *Plane) GetTransform() Matrix {
-	return plane.Transform
-}
-
-func (plane *Plane) GetInverse() Matrix {
-	if plane.cachedInverse == nil {
-		plane.cachedInverse = plane.Transform.Inverse()
-	}
-
-	return plane.cachedInverse
+func (plane *Plane) Bounds() Bounds {
+	inf := math.Inf(1)
+	return NewBounds(NewPoint(-inf, 0, -inf), NewPoint(inf, 0, inf))
 }
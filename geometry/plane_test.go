@@ -2,6 +2,7 @@ package geometry
 
 import (
 	. "go-raytracer/core"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,9 +10,9 @@ import (
 
 func TestPlaneNormalIsConstantEverywhere(t *testing.T) {
 	plane := NewPlane()
-	n1 := plane.NormalAt(NewPoint(0, 0, 0))
-	n2 := plane.NormalAt(NewPoint(10, 0, -10))
-	n3 := plane.NormalAt(NewPoint(-5, 0, 150))
+	n1 := plane.NormalAt(NewPoint(0, 0, 0), Intersection{})
+	n2 := plane.NormalAt(NewPoint(10, 0, -10), Intersection{})
+	n3 := plane.NormalAt(NewPoint(-5, 0, 150), Intersection{})
 
 	assert.Equal(t, NewVector(0, 1, 0), n1)
 	assert.Equal(t, NewVector(0, 1, 0), n2)
@@ -57,3 +58,10 @@ func TestRayIntersectsPlaneFromBelow(t *testing.T) {
 	assert.Equal(t, 1.0, xs[0].T)
 	assert.Equal(t, plane, xs[0].Object)
 }
+
+func TestNormalOnTransformedPlane(t *testing.T) {
+	plane := NewPlane()
+	plane.Transform = plane.Transform.RotateZ(math.Pi / 2)
+
+	EqualTuple(t, NewVector(-1, 0, 0), plane.NormalAt(NewPoint(0, 5, 0), Intersection{}))
+}
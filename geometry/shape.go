@@ -5,10 +5,78 @@ import (
 )
 
 type Shape interface {
-	NormalAt(point Tuple) Tuple
+	NormalAt(point Tuple, hit Intersection) Tuple
 	Intersects(ray Ray) []Intersection
 	GetMaterial() Material
 	SetMaterial(material Material)
 	GetTransform() Matrix
+	SetTransform(transform Matrix)
 	GetInverse() Matrix
+	// bounding box in object space
+	Bounds() Bounds
+	GetParent() Shape
+	SetParent(parent Shape)
+}
+
+// fields and methods shared by every shape
+type ShapeImpl struct {
+	Transform     Matrix
+	cachedInverse CachedInverse
+	Material      Material
+	parent        Shape
+}
+
+func NewShapeImpl() ShapeImpl {
+	return ShapeImpl{NewIdentityMatrix(), CachedInverse{}, NewMaterial(), nil}
+}
+
+func (shape *ShapeImpl) GetMaterial() Material {
+	return shape.Material
+}
+
+func (shape *ShapeImpl) SetMaterial(material Material) {
+	shape.Material = material
+}
+
+func (shape *ShapeImpl) GetTransform() Matrix {
+	return shape.Transform
+}
+
+func (shape *ShapeImpl) SetTransform(transform Matrix) {
+	shape.Transform = transform
+	shape.cachedInverse = CachedInverse{}
+}
+
+func (shape *ShapeImpl) GetInverse() Matrix {
+	return shape.cachedInverse.Get(shape.Transform)
+}
+
+func (shape *ShapeImpl) GetParent() Shape {
+	return shape.parent
+}
+
+func (shape *ShapeImpl) SetParent(parent Shape) {
+	shape.parent = parent
+}
+
+// converts a world space point to object space, through every parent group
+func WorldToObject(shape Shape, point Tuple) Tuple {
+	if parent := shape.GetParent(); parent != nil {
+		point = WorldToObject(parent, point)
+	}
+
+	return shape.GetInverse().MultiplyTuple(point)
+}
+
+// converts an object space normal to world space, through every parent group
+func NormalToWorld(shape Shape, normal Tuple) Tuple {
+	normal = shape.GetInverse().Transpose().MultiplyTuple(normal)
+	normal.W = 0
+	normal = normal.Normalize()
+
+	if parent := shape.GetParent(); parent != nil {
+		normal = NormalToWorld(parent, normal)
+	}
+
+	return normal
 }
@@ -0,0 +1,60 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+)
+
+// triangle whose normal is interpolated from the normals at its vertices
+type SmoothTriangle struct {
+	p1 Tuple
+	p2 Tuple
+	p3 Tuple
+	n1 Tuple
+	n2 Tuple
+	n3 Tuple
+	e1 Tuple
+	e2 Tuple
+	ShapeImpl
+}
+
+func NewSmoothTriangle(p1 Tuple, p2 Tuple, p3 Tuple, n1 Tuple, n2 Tuple, n3 Tuple) *SmoothTriangle {
+	e1 := p2.Subtract(p1)
+	e2 := p3.Subtract(p1)
+
+	return &SmoothTriangle{p1, p2, p3, n1, n2, n3, e1, e2, NewShapeImpl()}
+}
+
+func (triangle *SmoothTriangle) Intersects(ray Ray) []Intersection {
+	ray = ray.Transform(triangle.GetInverse())
+
+	t, u, v, ok := intersectTriangle(ray, triangle.p1, triangle.e1, triangle.e2)
+	if !ok {
+		return []Intersection{}
+	}
+
+	return []Intersection{NewIntersectionWithUV(t, triangle, u, v)}
+}
+
+func (triangle *SmoothTriangle) NormalAt(point Tuple, hit Intersection) Tuple {
+	objectNormal := triangle.n2.Multiply(hit.U).
+		Add(triangle.n3.Multiply(hit.V)).
+		Add(triangle.n1.Multiply(1 - hit.U - hit.V))
+
+	return NormalToWorld(triangle, objectNormal)
+}
+
+func (triangle *SmoothTriangle) Bounds() Bounds {
+	return NewEmptyBounds().AddPoint(triangle.p1).AddPoint(triangle.p2).AddPoint(triangle.p3)
+}
+
+func (triangle *SmoothTriangle) Area() float64 {
+	return ObjectToWorld(triangle, triangle.e1).Cross(ObjectToWorld(triangle, triangle.e2)).Magnitude() / 2
+}
+
+func (triangle *SmoothTriangle) SurfacePoint(u float64, v float64) (Tuple, Tuple) {
+	w2, w3 := triangleWeights(u, v)
+	point := triangle.p1.Add(triangle.e1.Multiply(w2)).Add(triangle.e2.Multiply(w3))
+	hit := NewIntersectionWithUV(0, triangle, w2, w3)
+
+	return ObjectToWorld(triangle, point), triangle.NormalAt(point, hit)
+}
@@ -0,0 +1,54 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSmoothTriangle() *SmoothTriangle {
+	return NewSmoothTriangle(
+		NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0),
+		NewVector(0, 1, 0), NewVector(-1, 0, 0), NewVector(1, 0, 0))
+}
+
+func TestNewSmoothTriangle(t *testing.T) {
+	triangle := newTestSmoothTriangle()
+
+	assert.Equal(t, NewPoint(0, 1, 0), triangle.p1)
+	assert.Equal(t, NewPoint(-1, 0, 0), triangle.p2)
+	assert.Equal(t, NewPoint(1, 0, 0), triangle.p3)
+	assert.Equal(t, NewVector(0, 1, 0), triangle.n1)
+	assert.Equal(t, NewVector(-1, 0, 0), triangle.n2)
+	assert.Equal(t, NewVector(1, 0, 0), triangle.n3)
+}
+
+func TestIntersectionWithUV(t *testing.T) {
+	s := NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0))
+
+	i := NewIntersectionWithUV(3.5, s, 0.2, 0.4)
+
+	assert.Equal(t, 0.2, i.U)
+	assert.Equal(t, 0.4, i.V)
+}
+
+func TestSmoothTriangleIntersectionStoresUV(t *testing.T) {
+	triangle := newTestSmoothTriangle()
+	r := NewRay(NewPoint(-0.2, 0.3, -2), NewVector(0, 0, 1))
+
+	xs := triangle.Intersects(r)
+
+	assert.Equal(t, 1, len(xs))
+	assert.InDelta(t, 0.45, xs[0].U, Epsilon)
+	assert.InDelta(t, 0.25, xs[0].V, Epsilon)
+}
+
+func TestSmoothTriangleInterpolatesNormal(t *testing.T) {
+	triangle := newTestSmoothTriangle()
+	i := NewIntersectionWithUV(1, triangle, 0.45, 0.25)
+
+	n := triangle.NormalAt(NewPoint(0, 0, 0), i)
+
+	EqualTuple(t, NewVector(-0.5547, 0.83205, 0), n)
+}
@@ -6,18 +6,16 @@ import (
 )
 
 type Sphere struct {
-	origin        Tuple
-	Transform     Matrix
-	cachedInverse Matrix
-	Material      Material
+	origin Tuple
+	ShapeImpl
 }
 
 func NewSphere() *Sphere {
-	return &Sphere{NewPoint(0, 0, 0), NewIdentityMatrix(), nil, NewMaterial()}
+	return &Sphere{NewPoint(0, 0, 0), NewShapeImpl()}
 }
 
 func NewGlassSphere() *Sphere {
-	sphere := Sphere{NewPoint(0, 0, 0), NewIdentityMatrix(), nil, NewMaterial()}
+	sphere := Sphere{NewPoint(0, 0, 0), NewShapeImpl()}
 	sphere.Material.Transparency = 1
 	sphere.Material.RefractiveIndex = 1.5
 
@@ -43,37 +41,66 @@ func (sphere *Sphere) Intersects(ray Ray) []Intersection {
 
 	t1 := (-b - math.Sqrt(discriminant)) / (2 * a)
 	t2 := (-b + math.Sqrt(discriminant)) / (2 * a)
-	xs = append(xs, Intersection{t1, sphere})
-	xs = append(xs, Intersection{t2, sphere})
+	xs = append(xs, NewIntersection(t1, sphere))
+	xs = append(xs, NewIntersection(t2, sphere))
 
 	return xs
 }
 
-func (sphere *Sphere) NormalAt(point Tuple) Tuple {
-	objectPoint := sphere.GetInverse().MultiplyTuple(point)
+func (sphere *Sphere) NormalAt(point Tuple, hit Intersection) Tuple {
+	objectPoint := WorldToObject(sphere, point)
 	objectNormal := objectPoint.Subtract(sphere.origin)
-	worldNormal := sphere.GetInverse().Transpose().MultiplyTuple(objectNormal)
-	worldNormal.W = 0
 
-	return worldNormal.Normalize()
+	return NormalToWorld(sphere, objectNormal)
 }
 
-func (sphere *Sphere) GetMaterial() Material {
-	return sphere.Material
+func (sphere *Sphere) Bounds() Bounds {
+	return NewBounds(NewPoint(-1, -1, -1), NewPoint(1, 1, 1))
 }
 
-func (sphere *Sphere) SetMaterial(material Material) {
-	sphere.Material = material
-}
+// exact for spheres scaled evenly and within about 1% for the ellipsoids
+// of uneven scaling, by Thomsen's formula
+func (sphere *Sphere) Area() float64 {
+	x, y, z := worldAxes(sphere)
+
+	// the squared semi-axes are the eigenvalues of the Gram matrix of the axes
+	a, b, c := symmetricEigenvalues(x.Dot(x), y.Dot(y), z.Dot(z), x.Dot(y), x.Dot(z), y.Dot(z))
+
+	const p = 1.6075
+	a, b, c = math.Pow(a, p/2), math.Pow(b, p/2), math.Pow(c, p/2)
 
-func (sphere *Sphere) GetTransform() Matrix {
-	return sphere.Transform
+	return 4 * math.Pi * math.Pow((a*b+a*c+b*c)/3, 1/p)
 }
 
-func (sphere *Sphere) GetInverse() Matrix {
-	if sphere.cachedInverse == nil {
-		sphere.cachedInverse = sphere.Transform.Inverse()
+// eigenvalues of the symmetric matrix with diagonal a11, a22, a33 and the
+// other entries a12, a13, a23, clamped to 0 and above
+func symmetricEigenvalues(a11, a22, a33, a12, a13, a23 float64) (float64, float64, float64) {
+	off := a12*a12 + a13*a13 + a23*a23
+	if off == 0 {
+		return math.Max(a11, 0), math.Max(a22, 0), math.Max(a33, 0)
 	}
 
-	return sphere.cachedInverse
+	q := (a11 + a22 + a33) / 3
+	p := math.Sqrt(((a11-q)*(a11-q) + (a22-q)*(a22-q) + (a33-q)*(a33-q) + 2*off) / 6)
+
+	// half the determinant of (A - qI) / p gives the angle of the roots
+	b11, b22, b33 := (a11-q)/p, (a22-q)/p, (a33-q)/p
+	b12, b13, b23 := a12/p, a13/p, a23/p
+	r := (b11*(b22*b33-b23*b23) - b12*(b12*b33-b23*b13) + b13*(b12*b23-b22*b13)) / 2
+	phi := math.Acos(math.Max(-1, math.Min(1, r))) / 3
+
+	first := q + 2*p*math.Cos(phi)
+	third := q + 2*p*math.Cos(phi+2*math.Pi/3)
+	second := 3*q - first - third
+
+	return math.Max(first, 0), math.Max(second, 0), math.Max(third, 0)
+}
+
+func (sphere *Sphere) SurfacePoint(u float64, v float64) (Tuple, Tuple) {
+	y := 1 - 2*u
+	radius := math.Sqrt(math.Max(0, 1-y*y))
+	phi := 2 * math.Pi * v
+	point := NewPoint(radius*math.Cos(phi), y, radius*math.Sin(phi))
+
+	return ObjectToWorld(sphere, point), NormalToWorld(sphere, point.Subtract(sphere.origin))
 }
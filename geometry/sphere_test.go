@@ -131,21 +131,21 @@ func TestIntersectingTranslatedSphereWithRay(t *testing.T) {
 
 func TestSphereNormalXAxis(t *testing.T) {
 	s := NewSphere()
-	n := s.NormalAt(NewPoint(1, 0, 0))
+	n := s.NormalAt(NewPoint(1, 0, 0), Intersection{})
 
 	assert.Equal(t, NewVector(1, 0, 0), n)
 }
 
 func TestSphereNormalYAxis(t *testing.T) {
 	s := NewSphere()
-	n := s.NormalAt(NewPoint(0, 1, 0))
+	n := s.NormalAt(NewPoint(0, 1, 0), Intersection{})
 
 	assert.Equal(t, NewVector(0, 1, 0), n)
 }
 
 func TestSphereNormalZAxis(t *testing.T) {
 	s := NewSphere()
-	n := s.NormalAt(NewPoint(0, 0, 1))
+	n := s.NormalAt(NewPoint(0, 0, 1), Intersection{})
 
 	assert.Equal(t, NewVector(0, 0, 1), n)
 }
@@ -153,7 +153,7 @@ func TestSphereNormalZAxis(t *testing.T) {
 func TestSphereNormalNonAxialPoint(t *testing.T) {
 	s := NewSphere()
 	x := math.Sqrt(3) / 3
-	n := s.NormalAt(NewPoint(x, x, x))
+	n := s.NormalAt(NewPoint(x, x, x), Intersection{})
 
 	assert.Equal(t, NewVector(x, x, x), n)
 }
@@ -161,7 +161,7 @@ func TestSphereNormalNonAxialPoint(t *testing.T) {
 func TestNormalIsNormalizedVector(t *testing.T) {
 	s := NewSphere()
 	x := math.Sqrt(3) / 3
-	n := s.NormalAt(NewPoint(x, x, x))
+	n := s.NormalAt(NewPoint(x, x, x), Intersection{})
 
 	assert.Equal(t, n.Normalize(), n)
 }
@@ -169,7 +169,7 @@ func TestNormalIsNormalizedVector(t *testing.T) {
 func TestNormalTranslatedSphere(t *testing.T) {
 	s := NewSphere()
 	s.Transform = s.Transform.Translate(0, 1, 0)
-	n := s.NormalAt(NewPoint(0, 1.70711, -0.70711))
+	n := s.NormalAt(NewPoint(0, 1.70711, -0.70711), Intersection{})
 
 	EqualTuple(t, NewVector(0, 0.70711, -0.70711), n)
 }
@@ -178,7 +178,16 @@ func TestNormalTransformedSphere(t *testing.T) {
 	s := NewSphere()
 	s.Transform = NewIdentityMatrix()
 	s.Transform = s.Transform.Scale(1, 0.5, 1).RotateZ(math.Pi / 5)
-	n := s.NormalAt(NewPoint(0, math.Sqrt2/2, -math.Sqrt2/2))
+	n := s.NormalAt(NewPoint(0, math.Sqrt2/2, -math.Sqrt2/2), Intersection{})
 
 	EqualTuple(t, NewVector(0, 0.97014, -0.24254), n)
 }
+
+func TestSetTransformResetsInverse(t *testing.T) {
+	s := NewSphere()
+	s.GetInverse()
+
+	s.SetTransform(NewIdentityMatrix().Translate(2, 3, 4))
+
+	assert.Equal(t, NewIdentityMatrix().Translate(2, 3, 4).Inverse(), s.GetInverse())
+}
@@ -0,0 +1,46 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"math"
+)
+
+// shapes with a finite surface that points can be picked on, such as the
+// surface of a light
+type SurfaceSampler interface {
+	Shape
+	// area of the surface in world space, after the transforms of the
+	// shape and its parents
+	Area() float64
+	// point on the surface and the normal there in world space, for u and v
+	// from 0 to 1. Evenly spread u and v give points evenly spread over
+	// the surface, except on spheres scaled by different amounts along
+	// their axes
+	SurfacePoint(u float64, v float64) (Tuple, Tuple)
+}
+
+// converts an object space point to world space, through every parent group
+func ObjectToWorld(shape Shape, point Tuple) Tuple {
+	point = shape.GetTransform().MultiplyTuple(point)
+
+	if parent := shape.GetParent(); parent != nil {
+		point = ObjectToWorld(parent, point)
+	}
+
+	return point
+}
+
+// the object space x, y and z axes of shape in world space
+func worldAxes(shape Shape) (Tuple, Tuple, Tuple) {
+	return ObjectToWorld(shape, NewVector(1, 0, 0)),
+		ObjectToWorld(shape, NewVector(0, 1, 0)),
+		ObjectToWorld(shape, NewVector(0, 0, 1))
+}
+
+// barycentric weights of the second and third corner of a triangle for u
+// and v from 0 to 1, spread evenly over its area
+func triangleWeights(u float64, v float64) (float64, float64) {
+	s := math.Sqrt(u)
+
+	return s * (1 - v), s * v
+}
@@ -0,0 +1,174 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// u and v pairs spread over the unit square, including its corners
+var surfaceUVs = [][2]float64{{0, 0}, {1, 1}, {0.5, 0.5}, {0.1, 0.9}, {0.3, 0.2}, {0.99, 0.4}, {0.7, 0}}
+
+func TestObjectToWorldThroughGroups(t *testing.T) {
+	g1 := NewGroup()
+	g1.Transform = g1.Transform.RotateY(math.Pi / 2)
+	g2 := NewGroup()
+	g2.Transform = g2.Transform.Scale(2, 2, 2)
+	g1.AddChild(g2)
+	s := NewSphere()
+	s.Transform = s.Transform.Translate(5, 0, 0)
+	g2.AddChild(s)
+
+	EqualTuple(t, NewPoint(-2, 0, -10), ObjectToWorld(s, NewPoint(0, 0, -1)))
+}
+
+func TestSphereSurfacePoints(t *testing.T) {
+	sphere := NewSphere()
+	sphere.Transform = sphere.Transform.Translate(1, 2, 3).Scale(2, 2, 2)
+	center := NewPoint(1, 2, 3)
+
+	assert.InDelta(t, 16*math.Pi, sphere.Area(), 1e-9)
+
+	for _, uv := range surfaceUVs {
+		point, normal := sphere.SurfacePoint(uv[0], uv[1])
+
+		assert.InDelta(t, 2, point.Subtract(center).Magnitude(), 1e-9)
+		EqualTuple(t, point.Subtract(center).Normalize(), normal)
+	}
+}
+
+func TestAreaOfUnevenlyScaledSphere(t *testing.T) {
+	sphere := NewSphere()
+	sphere.Transform = sphere.Transform.Scale(2, 1, 1)
+
+	// a prolate spheroid with semi-axes 2, 1 and 1
+	e := math.Sqrt(3) / 2
+	expected := 2 * math.Pi * (1 + 2/e*math.Asin(e))
+	assert.InEpsilon(t, expected, sphere.Area(), 0.01)
+
+	// rotating it does not change its area
+	rotated := NewSphere()
+	rotated.Transform = rotated.Transform.RotateZ(0.7).RotateY(0.3).Scale(2, 1, 1)
+	assert.InDelta(t, sphere.Area(), rotated.Area(), 1e-9)
+}
+
+func TestCubeSurfacePoints(t *testing.T) {
+	cube := NewCube()
+	faces := map[Tuple]bool{}
+
+	assert.Equal(t, 24.0, cube.Area())
+
+	for i := 0; i < 6; i++ {
+		point, normal := cube.SurfacePoint((float64(i)+0.5)/6, 0.5)
+
+		EqualTuple(t, normal, point.Subtract(NewPoint(0, 0, 0)))
+		EqualTuple(t, cube.NormalAt(point, Intersection{}), normal)
+		faces[normal] = true
+	}
+
+	assert.Len(t, faces, 6)
+
+	// faces are picked in proportion to their area
+	long := NewCube()
+	long.Transform = long.Transform.Scale(10, 1, 1)
+	assert.InDelta(t, 168, long.Area(), 1e-9)
+
+	for _, u := range []float64{0, 0.01, 0.02} {
+		_, normal := long.SurfacePoint(u, 0.5)
+		EqualTuple(t, NewVector(1, 0, 0), normal)
+	}
+
+	_, normal := long.SurfacePoint(0.03, 0.5)
+	EqualTuple(t, NewVector(-1, 0, 0), normal)
+
+	for _, uv := range surfaceUVs {
+		point, normal := cube.SurfacePoint(uv[0], uv[1])
+
+		largest := math.Max(math.Abs(point.X), math.Max(math.Abs(point.Y), math.Abs(point.Z)))
+		assert.InDelta(t, 1, largest, 1e-9)
+		assert.InDelta(t, 1, normal.Dot(point), 1e-9)
+	}
+}
+
+func TestTriangleSurfacePoints(t *testing.T) {
+	triangle := NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0))
+
+	assert.Equal(t, 1.0, triangle.Area())
+
+	tests := []struct {
+		u, v     float64
+		expected Tuple
+	}{
+		{0, 0.5, NewPoint(0, 1, 0)},
+		{1, 0, NewPoint(-1, 0, 0)},
+		{1, 1, NewPoint(1, 0, 0)},
+		{0.25, 0.5, NewPoint(0, 0.5, 0)},
+	}
+
+	for _, test := range tests {
+		point, normal := triangle.SurfacePoint(test.u, test.v)
+
+		EqualTuple(t, test.expected, point)
+		EqualTuple(t, NewVector(0, 0, -1), normal)
+	}
+}
+
+func TestSmoothTriangleSurfaceNormals(t *testing.T) {
+	triangle := NewSmoothTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0),
+		NewVector(0, 1, 0), NewVector(-1, 0, 0), NewVector(1, 0, 0))
+
+	point, normal := triangle.SurfacePoint(1, 0)
+
+	EqualTuple(t, NewPoint(-1, 0, 0), point)
+	EqualTuple(t, NewVector(-1, 0, 0), normal)
+}
+
+func TestGroupSurfacePointsByArea(t *testing.T) {
+	small := NewTriangle(NewPoint(0, 0, 0), NewPoint(1, 0, 0), NewPoint(0, 2, 0))
+	large := NewTriangle(NewPoint(0, 0, 5), NewPoint(3, 0, 5), NewPoint(0, 2, 5))
+	group := NewGroup()
+	group.AddChild(small, NewPlane(), large)
+	group.Transform = group.Transform.Translate(0, 10, 0)
+
+	assert.Equal(t, 4.0, group.Area())
+
+	for _, u := range []float64{0, 0.1, 0.24} {
+		point, _ := group.SurfacePoint(u, 0.5)
+		assert.Equal(t, 0.0, point.Z, u)
+		assert.GreaterOrEqual(t, point.Y, 10.0)
+	}
+
+	for _, u := range []float64{0.26, 0.5, 1} {
+		point, _ := group.SurfacePoint(u, 0.5)
+		assert.Equal(t, 5.0, point.Z, u)
+	}
+}
+
+func TestGroupWithoutSurfacePanics(t *testing.T) {
+	group := NewGroup()
+	group.AddChild(NewPlane())
+
+	assert.Panics(t, func() { group.SurfacePoint(0.5, 0.5) })
+}
+
+func TestGroupAreaInWorldSpace(t *testing.T) {
+	large := NewSphere()
+	large.Transform = large.Transform.Translate(20, 0, 0).Scale(10, 10, 10)
+	small := NewSphere()
+	group := NewGroup()
+	group.AddChild(large, small)
+	group.Transform = group.Transform.Scale(2, 2, 2)
+
+	assert.InDelta(t, 4*4*math.Pi*101, group.Area(), 1e-6)
+
+	// the large sphere gets its share of the points by its area
+	for _, u := range []float64{0, 0.5, 0.99} {
+		point, _ := group.SurfacePoint(u, 0.5)
+		assert.Greater(t, point.X, 10.0, u)
+	}
+
+	point, _ := group.SurfacePoint(0.995, 0.5)
+	assert.Less(t, point.Subtract(NewPoint(0, 0, 0)).Magnitude(), 3.0)
+}
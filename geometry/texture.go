@@ -0,0 +1,111 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/image"
+	"math"
+)
+
+// pattern over texture coordinates between 0 and 1
+type UVPattern interface {
+	UVColorAt(u float64, v float64) Color
+}
+
+type TextureFilter int
+
+const (
+	FilterNearest TextureFilter = iota
+	FilterBilinear
+)
+
+// pattern that maps points to texture coordinates and looks them up in a
+// uv pattern
+type TextureMapPattern struct {
+	texture UVPattern
+	mapping UVMapping
+	PatternImpl
+}
+
+// checkers with width squares along u and height squares along v
+type UVCheckers struct {
+	width  float64
+	height float64
+	a      Color
+	b      Color
+}
+
+// texture that samples a canvas, v = 0 is the bottom row of the canvas
+type ImageTexture struct {
+	canvas Canvas
+	Filter TextureFilter
+}
+
+func NewTextureMapPattern(texture UVPattern, mapping UVMapping) *TextureMapPattern {
+	return &TextureMapPattern{texture, mapping, PatternImpl{NewIdentityMatrix(), CachedInverse{}}}
+}
+
+func NewUVCheckers(width float64, height float64, a Color, b Color) *UVCheckers {
+	return &UVCheckers{width, height, a, b}
+}
+
+func NewImageTexture(canvas Canvas, filter TextureFilter) *ImageTexture {
+	if canvas.Width() == 0 || canvas.Height() == 0 {
+		panic("precondition - image texture needs a canvas with pixels")
+	}
+
+	return &ImageTexture{canvas, filter}
+}
+
+func (pattern *TextureMapPattern) ColorAt(point Tuple) Color {
+	u, v := pattern.mapping(point)
+
+	return pattern.texture.UVColorAt(u, v)
+}
+
+func (pattern *UVCheckers) UVColorAt(u float64, v float64) Color {
+	if int(math.Floor(u*pattern.width)+math.Floor(v*pattern.height))%2 == 0 {
+		return pattern.a
+	}
+
+	return pattern.b
+}
+
+func (texture *ImageTexture) UVColorAt(u float64, v float64) Color {
+	// pixel centers are at whole numbers, u and v outside 0:1 are clamped
+	x := clamp(finite(u), 0, 1) * float64(texture.canvas.Width()-1)
+	y := (1 - clamp(finite(v), 0, 1)) * float64(texture.canvas.Height()-1)
+
+	if texture.Filter == FilterNearest {
+		return texture.pixel(math.Round(x), math.Round(y))
+	}
+
+	x0, y0 := math.Floor(x), math.Floor(y)
+	x1, y1 := math.Ceil(x), math.Ceil(y)
+	fx, fy := x-x0, y-y0
+
+	top := lerpColor(texture.pixel(x0, y0), texture.pixel(x1, y0), fx)
+	bottom := lerpColor(texture.pixel(x0, y1), texture.pixel(x1, y1), fx)
+
+	return lerpColor(top, bottom, fy)
+}
+
+func (texture *ImageTexture) pixel(x float64, y float64) Color {
+	return texture.canvas.Pixel[int(x)][int(y)]
+}
+
+func lerpColor(a Color, b Color, t float64) Color {
+	return a.Add(b.Subtract(a).MultiplyScalar(t))
+}
+
+// NaN and infinite coordinates, say from a degenerate mapping, read as 0
+func finite(x float64) float64 {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return 0
+	}
+
+	return x
+}
+
+func clamp(x float64, low float64, high float64) float64 {
+	return math.Max(low, math.Min(high, x))
+}
@@ -0,0 +1,114 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/image"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// canvas where each channel of pixel (x, y) is ((x + y) mod 10) / 10
+func gradientCanvas() Canvas {
+	canvas := NewCanvas(10, 10)
+
+	for x := uint(0); x < 10; x++ {
+		for y := uint(0); y < 10; y++ {
+			value := float64((x+y)%10) / 10
+			canvas.WritePixel(x, y, NewColor(value, value, value))
+		}
+	}
+
+	return canvas
+}
+
+func TestUVCheckers(t *testing.T) {
+	checkers := NewUVCheckers(2, 2, Black, White)
+
+	assert.Equal(t, Black, checkers.UVColorAt(0, 0))
+	assert.Equal(t, White, checkers.UVColorAt(0.5, 0))
+	assert.Equal(t, White, checkers.UVColorAt(0, 0.5))
+	assert.Equal(t, Black, checkers.UVColorAt(0.5, 0.5))
+	assert.Equal(t, Black, checkers.UVColorAt(1, 1))
+}
+
+func TestImageTextureNearest(t *testing.T) {
+	texture := NewImageTexture(gradientCanvas(), FilterNearest)
+
+	assert.Equal(t, NewColor(0.9, 0.9, 0.9), texture.UVColorAt(0, 0))
+	assert.Equal(t, NewColor(0.2, 0.2, 0.2), texture.UVColorAt(0.3, 0))
+	assert.Equal(t, NewColor(0.1, 0.1, 0.1), texture.UVColorAt(0.6, 0.3))
+	assert.Equal(t, NewColor(0.9, 0.9, 0.9), texture.UVColorAt(1, 1))
+}
+
+func TestImageTextureClampsCoordinates(t *testing.T) {
+	texture := NewImageTexture(gradientCanvas(), FilterNearest)
+
+	assert.Equal(t, texture.UVColorAt(0, 0), texture.UVColorAt(-0.5, -2))
+	assert.Equal(t, texture.UVColorAt(1, 1), texture.UVColorAt(1.5, 3))
+}
+
+func TestImageTextureNonFiniteCoordinates(t *testing.T) {
+	for _, filter := range []TextureFilter{FilterNearest, FilterBilinear} {
+		texture := NewImageTexture(gradientCanvas(), filter)
+
+		assert.Equal(t, texture.UVColorAt(0, 0), texture.UVColorAt(math.NaN(), math.NaN()))
+		assert.Equal(t, texture.UVColorAt(0, 0.3), texture.UVColorAt(math.Inf(1), 0.3))
+		assert.Equal(t, texture.UVColorAt(0.6, 0), texture.UVColorAt(0.6, math.Inf(-1)))
+	}
+}
+
+func TestImageTextureBilinear(t *testing.T) {
+	canvas := NewCanvas(2, 2)
+	canvas.WritePixel(0, 0, White)
+	canvas.WritePixel(1, 0, Red)
+	texture := NewImageTexture(canvas, FilterBilinear)
+
+	assert.Equal(t, White, texture.UVColorAt(0, 1))
+	assert.Equal(t, Red, texture.UVColorAt(1, 1))
+	assert.Equal(t, NewColor(1, 0.5, 0.5), texture.UVColorAt(0.5, 1))
+	assert.Equal(t, NewColor(0.5, 0.25, 0.25), texture.UVColorAt(0.5, 0.5))
+	assert.Equal(t, Black, texture.UVColorAt(0.3, 0))
+}
+
+func TestImageTextureNeedsPixels(t *testing.T) {
+	assert.Panics(t, func() { NewImageTexture(NewCanvas(0, 3), FilterNearest) })
+}
+
+func TestTextureMapPatternOnSphere(t *testing.T) {
+	object := NewSphere()
+	object.Transform = object.Transform.Translate(5, 0, 0)
+	pattern := NewTextureMapPattern(NewUVCheckers(16, 8, Black, White), SphericalMap)
+
+	tests := []struct {
+		point    Tuple
+		expected Color
+	}{
+		{NewPoint(0.4315, 0.4670, 0.7719), White},
+		{NewPoint(-0.9654, 0.2552, -0.0534), Black},
+		{NewPoint(0.1039, 0.7090, 0.6975), White},
+		{NewPoint(-0.4986, -0.7856, -0.3663), Black},
+		{NewPoint(-0.0317, -0.9395, 0.3411), Black},
+		{NewPoint(0.4809, -0.7721, 0.4154), Black},
+		{NewPoint(0.0285, -0.9612, -0.2745), Black},
+		{NewPoint(-0.5734, -0.2162, -0.7903), White},
+		{NewPoint(0.7688, -0.1470, 0.6223), Black},
+		{NewPoint(-0.7652, 0.2175, 0.6060), Black},
+	}
+
+	for _, test := range tests {
+		world := test.point.Add(NewVector(5, 0, 0))
+
+		assert.Equal(t, test.expected, PatternColor(pattern, object, world), test.point)
+	}
+}
+
+func TestTextureMapPatternUsesPatternTransform(t *testing.T) {
+	object := NewPlane()
+	pattern := NewTextureMapPattern(NewUVCheckers(2, 2, Black, White), PlanarMap)
+	pattern.Transform = pattern.Transform.Scale(2, 2, 2)
+
+	assert.Equal(t, Black, PatternColor(pattern, object, NewPoint(0.9, 0, 0.9)))
+	assert.Equal(t, White, PatternColor(pattern, object, NewPoint(1.1, 0, 0.9)))
+}
@@ -0,0 +1,85 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"math"
+)
+
+type Triangle struct {
+	p1     Tuple
+	p2     Tuple
+	p3     Tuple
+	e1     Tuple
+	e2     Tuple
+	normal Tuple
+	ShapeImpl
+}
+
+func NewTriangle(p1 Tuple, p2 Tuple, p3 Tuple) *Triangle {
+	e1 := p2.Subtract(p1)
+	e2 := p3.Subtract(p1)
+	normal := e2.Cross(e1).Normalize()
+
+	return &Triangle{p1, p2, p3, e1, e2, normal, NewShapeImpl()}
+}
+
+func (triangle *Triangle) Intersects(ray Ray) []Intersection {
+	ray = ray.Transform(triangle.GetInverse())
+
+	t, u, v, ok := intersectTriangle(ray, triangle.p1, triangle.e1, triangle.e2)
+	if !ok {
+		return []Intersection{}
+	}
+
+	return []Intersection{NewIntersectionWithUV(t, triangle, u, v)}
+}
+
+func (triangle *Triangle) NormalAt(point Tuple, hit Intersection) Tuple {
+	return NormalToWorld(triangle, triangle.normal)
+}
+
+// Möller–Trumbore ray/triangle intersection in object space, returns the
+// distance along the ray and the barycentric coordinates of the hit
+func intersectTriangle(ray Ray, p1 Tuple, e1 Tuple, e2 Tuple) (float64, float64, float64, bool) {
+	dirCrossE2 := ray.Direction.Cross(e2)
+	determinant := e1.Dot(dirCrossE2)
+
+	// ray is parallel to the triangle
+	if math.Abs(determinant) < Epsilon {
+		return 0, 0, 0, false
+	}
+
+	f := 1 / determinant
+	p1ToOrigin := ray.Origin.Subtract(p1)
+	u := f * p1ToOrigin.Dot(dirCrossE2)
+
+	if u < 0 || u > 1 {
+		return 0, 0, 0, false
+	}
+
+	originCrossE1 := p1ToOrigin.Cross(e1)
+	v := f * ray.Direction.Dot(originCrossE1)
+
+	if v < 0 || u+v > 1 {
+		return 0, 0, 0, false
+	}
+
+	t := f * e2.Dot(originCrossE1)
+
+	return t, u, v, true
+}
+
+func (triangle *Triangle) Bounds() Bounds {
+	return NewEmptyBounds().AddPoint(triangle.p1).AddPoint(triangle.p2).AddPoint(triangle.p3)
+}
+
+func (triangle *Triangle) Area() float64 {
+	return ObjectToWorld(triangle, triangle.e1).Cross(ObjectToWorld(triangle, triangle.e2)).Magnitude() / 2
+}
+
+func (triangle *Triangle) SurfacePoint(u float64, v float64) (Tuple, Tuple) {
+	w2, w3 := triangleWeights(u, v)
+	point := triangle.p1.Add(triangle.e1.Multiply(w2)).Add(triangle.e2.Multiply(w3))
+
+	return ObjectToWorld(triangle, point), NormalToWorld(triangle, triangle.normal)
+}
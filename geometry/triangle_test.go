@@ -0,0 +1,67 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTriangle(t *testing.T) {
+	p1 := NewPoint(0, 1, 0)
+	p2 := NewPoint(-1, 0, 0)
+	p3 := NewPoint(1, 0, 0)
+
+	triangle := NewTriangle(p1, p2, p3)
+
+	assert.Equal(t, p1, triangle.p1)
+	assert.Equal(t, p2, triangle.p2)
+	assert.Equal(t, p3, triangle.p3)
+	assert.Equal(t, NewVector(-1, -1, 0), triangle.e1)
+	assert.Equal(t, NewVector(1, -1, 0), triangle.e2)
+	assert.Equal(t, NewVector(0, 0, -1), triangle.normal)
+}
+
+func TestNormalOfTriangle(t *testing.T) {
+	triangle := NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0))
+
+	n1 := triangle.NormalAt(NewPoint(0, 0.5, 0), Intersection{})
+	n2 := triangle.NormalAt(NewPoint(-0.5, 0.75, 0), Intersection{})
+	n3 := triangle.NormalAt(NewPoint(0.5, 0.25, 0), Intersection{})
+
+	assert.Equal(t, triangle.normal, n1)
+	assert.Equal(t, triangle.normal, n2)
+	assert.Equal(t, triangle.normal, n3)
+}
+
+func TestRayMissesTriangle(t *testing.T) {
+	examples := [][]Tuple{
+		// parallel to the triangle
+		{NewPoint(0, -1, -2), NewVector(0, 1, 0)},
+		// misses the p1-p3 edge
+		{NewPoint(1, 1, -2), NewVector(0, 0, 1)},
+		// misses the p1-p2 edge
+		{NewPoint(-1, 1, -2), NewVector(0, 0, 1)},
+		// misses the p2-p3 edge
+		{NewPoint(0, -1, -2), NewVector(0, 0, 1)},
+	}
+
+	triangle := NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0))
+
+	for i := range examples {
+		r := NewRay(examples[i][0], examples[i][1])
+		xs := triangle.Intersects(r)
+
+		assert.Equal(t, 0, len(xs))
+	}
+}
+
+func TestRayStrikesTriangle(t *testing.T) {
+	triangle := NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0))
+	r := NewRay(NewPoint(0, 0.5, -2), NewVector(0, 0, 1))
+
+	xs := triangle.Intersects(r)
+
+	assert.Equal(t, 1, len(xs))
+	assert.Equal(t, 2.0, xs[0].T)
+}
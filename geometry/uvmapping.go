@@ -0,0 +1,123 @@
+package geometry
+
+import (
+	. "go-raytracer/core"
+	"math"
+)
+
+// maps a point in pattern space to texture coordinates between 0 and 1,
+// with v increasing upwards
+type UVMapping func(point Tuple) (float64, float64)
+
+type CubeFace int
+
+const (
+	CubeLeft CubeFace = iota
+	CubeRight
+	CubeFront
+	CubeBack
+	CubeUp
+	CubeDown
+)
+
+// column and row of each face in the CubeMap cross, counted from the
+// bottom left
+var cubeCrossCells = map[CubeFace][2]float64{
+	CubeLeft:  {0, 1},
+	CubeFront: {1, 1},
+	CubeRight: {2, 1},
+	CubeBack:  {3, 1},
+	CubeUp:    {1, 2},
+	CubeDown:  {1, 0},
+}
+
+// repeats the texture every unit along x and z
+func PlanarMap(point Tuple) (float64, float64) {
+	return positiveMod(point.X, 1), positiveMod(point.Z, 1)
+}
+
+// wraps the texture around a sphere centered on the origin, u runs around
+// the y axis and v from the south to the north pole
+func SphericalMap(point Tuple) (float64, float64) {
+	theta := math.Atan2(point.X, point.Z)
+	radius := NewVector(point.X, point.Y, point.Z).Magnitude()
+	if radius == 0 {
+		return 0.5, 0.5
+	}
+
+	phi := math.Acos(point.Y / radius)
+
+	return 1 - (theta/(2*math.Pi) + 0.5), 1 - phi/math.Pi
+}
+
+// wraps the texture around the y axis, repeating every unit along y
+func CylindricalMap(point Tuple) (float64, float64) {
+	theta := math.Atan2(point.X, point.Z)
+
+	return 1 - (theta/(2*math.Pi) + 0.5), positiveMod(point.Y, 1)
+}
+
+// maps each face of the unit cube onto a texture laid out as a cross, 4
+// faces wide and 3 faces high:
+//
+//	     up
+//	left front right back
+//	     down
+func CubeMap(point Tuple) (float64, float64) {
+	face := CubeFaceOf(point)
+	u, v := CubeFaceUV(face, point)
+
+	cell := cubeCrossCells[face]
+
+	return (cell[0] + u) / 4, (cell[1] + v) / 3
+}
+
+// face of the unit cube the point lies on, or is closest to
+func CubeFaceOf(point Tuple) CubeFace {
+	absX, absY, absZ := math.Abs(point.X), math.Abs(point.Y), math.Abs(point.Z)
+	coordinate := math.Max(absX, math.Max(absY, absZ))
+
+	switch coordinate {
+	case point.X:
+		return CubeRight
+	case -point.X:
+		return CubeLeft
+	case point.Y:
+		return CubeUp
+	case -point.Y:
+		return CubeDown
+	case point.Z:
+		return CubeFront
+	}
+
+	return CubeBack
+}
+
+// texture coordinates within one face of the unit cube, the faces around
+// the y axis join up left to right in the order left, front, right, back
+func CubeFaceUV(face CubeFace, point Tuple) (float64, float64) {
+	switch face {
+	case CubeLeft:
+		return positiveMod(point.Z+1, 2) / 2, positiveMod(point.Y+1, 2) / 2
+	case CubeRight:
+		return positiveMod(1-point.Z, 2) / 2, positiveMod(point.Y+1, 2) / 2
+	case CubeFront:
+		return positiveMod(point.X+1, 2) / 2, positiveMod(point.Y+1, 2) / 2
+	case CubeBack:
+		return positiveMod(1-point.X, 2) / 2, positiveMod(point.Y+1, 2) / 2
+	case CubeUp:
+		return positiveMod(point.X+1, 2) / 2, positiveMod(1-point.Z, 2) / 2
+	}
+
+	return positiveMod(point.X+1, 2) / 2, positiveMod(point.Z+1, 2) / 2
+}
+
+// remainder of x / y with the sign of y
+func positiveMod(x float64, y float64) float64 {
+	result := math.Mod(x, y)
+	if result < 0 {
+		result += y
+	}
+
+	return result
+}
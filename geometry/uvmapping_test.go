@@ -0,0 +1,108 @@
+package geometry
+
+import (
+	"fmt"
+	. "go-raytracer/core"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type uvTest struct {
+	point Tuple
+	u     float64
+	v     float64
+}
+
+func assertMapping(t *testing.T, mapping UVMapping, tests []uvTest) {
+	for _, test := range tests {
+		u, v := mapping(test.point)
+
+		assert.InDelta(t, test.u, u, Epsilon, fmt.Sprint(test.point))
+		assert.InDelta(t, test.v, v, Epsilon, fmt.Sprint(test.point))
+	}
+}
+
+func TestSphericalMap(t *testing.T) {
+	assertMapping(t, SphericalMap, []uvTest{
+		{NewPoint(0, 0, -1), 0, 0.5},
+		{NewPoint(1, 0, 0), 0.25, 0.5},
+		{NewPoint(0, 0, 1), 0.5, 0.5},
+		{NewPoint(-1, 0, 0), 0.75, 0.5},
+		{NewPoint(0, 1, 0), 0.5, 1},
+		{NewPoint(0, -1, 0), 0.5, 0},
+		{NewPoint(math.Sqrt2/2, math.Sqrt2/2, 0), 0.25, 0.75},
+		{NewPoint(0, 0, 0), 0.5, 0.5},
+	})
+}
+
+func TestPlanarMap(t *testing.T) {
+	assertMapping(t, PlanarMap, []uvTest{
+		{NewPoint(0.25, 0, 0.5), 0.25, 0.5},
+		{NewPoint(0.25, 0, -0.25), 0.25, 0.75},
+		{NewPoint(0.25, 0.5, -0.25), 0.25, 0.75},
+		{NewPoint(1.25, 0, 0.5), 0.25, 0.5},
+		{NewPoint(0.25, 0, -1.75), 0.25, 0.25},
+		{NewPoint(1, 0, -1), 0, 0},
+		{NewPoint(0, 0, 0), 0, 0},
+	})
+}
+
+func TestCylindricalMap(t *testing.T) {
+	assertMapping(t, CylindricalMap, []uvTest{
+		{NewPoint(0, 0, -1), 0, 0},
+		{NewPoint(0, 0.5, -1), 0, 0.5},
+		{NewPoint(0, 1, -1), 0, 0},
+		{NewPoint(math.Sqrt2/2, 0.5, -math.Sqrt2/2), 0.125, 0.5},
+		{NewPoint(1, 0.5, 0), 0.25, 0.5},
+		{NewPoint(math.Sqrt2/2, 0.5, math.Sqrt2/2), 0.375, 0.5},
+		{NewPoint(0, -0.25, 1), 0.5, 0.75},
+		{NewPoint(-math.Sqrt2/2, 0.5, math.Sqrt2/2), 0.625, 0.5},
+		{NewPoint(-1, 1.25, 0), 0.75, 0.25},
+		{NewPoint(-math.Sqrt2/2, 0.5, -math.Sqrt2/2), 0.875, 0.5},
+	})
+}
+
+func TestCubeFaceOf(t *testing.T) {
+	assert.Equal(t, CubeLeft, CubeFaceOf(NewPoint(-1, 0.5, -0.25)))
+	assert.Equal(t, CubeRight, CubeFaceOf(NewPoint(1.1, -0.75, 0.8)))
+	assert.Equal(t, CubeFront, CubeFaceOf(NewPoint(0.1, 0.6, 0.9)))
+	assert.Equal(t, CubeBack, CubeFaceOf(NewPoint(-0.7, 0, -2)))
+	assert.Equal(t, CubeUp, CubeFaceOf(NewPoint(0.5, 1, 0.9)))
+	assert.Equal(t, CubeDown, CubeFaceOf(NewPoint(-0.2, -1.3, 1.1)))
+}
+
+func TestCubeFaceUV(t *testing.T) {
+	tests := []struct {
+		face   CubeFace
+		first  Tuple
+		second Tuple
+	}{
+		{CubeFront, NewPoint(-0.5, 0.5, 1), NewPoint(0.5, -0.5, 1)},
+		{CubeBack, NewPoint(0.5, 0.5, -1), NewPoint(-0.5, -0.5, -1)},
+		{CubeLeft, NewPoint(-1, 0.5, -0.5), NewPoint(-1, -0.5, 0.5)},
+		{CubeRight, NewPoint(1, 0.5, 0.5), NewPoint(1, -0.5, -0.5)},
+		{CubeUp, NewPoint(-0.5, 1, -0.5), NewPoint(0.5, 1, 0.5)},
+		{CubeDown, NewPoint(-0.5, -1, 0.5), NewPoint(0.5, -1, -0.5)},
+	}
+
+	for _, test := range tests {
+		u, v := CubeFaceUV(test.face, test.first)
+		assert.Equal(t, []float64{0.25, 0.75}, []float64{u, v}, test.face)
+
+		u, v = CubeFaceUV(test.face, test.second)
+		assert.Equal(t, []float64{0.75, 0.25}, []float64{u, v}, test.face)
+	}
+}
+
+func TestCubeMapLaysOutCross(t *testing.T) {
+	assertMapping(t, CubeMap, []uvTest{
+		{NewPoint(-1, 0, 0), 0.125, 0.5},
+		{NewPoint(0, 0, 1), 0.375, 0.5},
+		{NewPoint(1, 0, 0), 0.625, 0.5},
+		{NewPoint(0, 0, -1), 0.875, 0.5},
+		{NewPoint(0, 1, 0), 0.375, 5.0 / 6},
+		{NewPoint(0, -1, 0), 0.375, 1.0 / 6},
+	})
+}
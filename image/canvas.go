@@ -1,9 +1,7 @@
 package image
 
 import (
-	"fmt"
 	. "go-raytracer/core"
-	"strconv"
 	"strings"
 )
 
@@ -22,55 +20,38 @@ func NewCanvas(width, height uint) Canvas {
 	return Canvas{width, height, pixel}
 }
 
+func (canvas Canvas) Width() uint {
+	return canvas.width
+}
+
+func (canvas Canvas) Height() uint {
+	return canvas.height
+}
+
 func (canvas Canvas) WritePixel(x, y uint, color Color) {
 	canvas.Pixel[x][y] = color
 }
 
+// the whole canvas as P3 text, prefer WritePPM for large canvases
 func (canvas Canvas) ToPPM() string {
-	const maxCharPerLine = 70
-	const maxCharPixel = 5
-
 	data := strings.Builder{}
-
-	header := fmt.Sprintf("P3\n%d %d\n255\n", canvas.width, canvas.height)
-	data.WriteString(header)
-
-	for j := uint(0); j < canvas.height; j++ {
-		row := ""
-		for i := uint(0); i < canvas.width; i++ {
-			pixel := []string{
-				strconv.Itoa(scaleFloat(canvas.Pixel[i][j].Red)),
-				strconv.Itoa(scaleFloat(canvas.Pixel[i][j].Green)),
-				strconv.Itoa(scaleFloat(canvas.Pixel[i][j].Blue))}
-
-			for _, color := range pixel {
-				if len(row)+maxCharPixel > maxCharPerLine {
-					row += "\n"
-					data.WriteString(row)
-					row = ""
-				}
-
-				if row != "" {
-					row += " "
-				}
-
-				row += color
-			}
-		}
-		row += "\n"
-		data.WriteString(row)
-	}
+	canvas.WritePPM(&data, PPMOptions{})
 
 	return data.String()
 }
 
 // scale from float 0:1 to int 0:255
 func scaleFloat(x float64) int {
+	return scaleTo(x, 255)
+}
+
+// scale from float 0:1 to int 0:maxValue
+func scaleTo(x float64, maxValue int) int {
 	if x <= 0 {
 		return 0
 	} else if x >= 1 {
-		return 255
+		return maxValue
 	} else {
-		return int(x * 256)
+		return int(x * float64(maxValue+1))
 	}
 }
@@ -0,0 +1,334 @@
+package image
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	. "go-raytracer/core"
+	stdimage "image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"strconv"
+)
+
+// limits the size of image a decoder accepts, a canvas uses 24 bytes per
+// pixel. PPM pixels are only stored as their rows arrive, so a header alone
+// cannot make the decoder allocate the whole canvas
+const maxDecodePixels = 1 << 25
+
+// canvas from a PPM or PNG image, the format is detected from its contents
+func Decode(reader io.Reader) (Canvas, error) {
+	buffered := bufio.NewReader(reader)
+
+	magic, _ := buffered.Peek(8)
+	switch {
+	case bytes.HasPrefix(magic, []byte("P3")), bytes.HasPrefix(magic, []byte("P6")):
+		return ReadPPM(buffered)
+	case bytes.HasPrefix(magic, []byte("\x89PNG\r\n\x1a\n")):
+		return ReadPNG(buffered)
+	}
+
+	return Canvas{}, errors.New("image is not a PPM or PNG file")
+}
+
+func LoadFile(path string) (Canvas, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Canvas{}, err
+	}
+	defer file.Close()
+
+	canvas, err := Decode(file)
+	if err != nil {
+		return Canvas{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return canvas, nil
+}
+
+// canvas from a P3 or P6 PPM, samples are scaled by maxval to 0:1
+func ReadPPM(reader io.Reader) (Canvas, error) {
+	ppm := ppmReader{reader: bufio.NewReader(reader)}
+
+	magic, err := ppm.token("magic number")
+	if err != nil {
+		return Canvas{}, err
+	}
+
+	if magic != "P3" && magic != "P6" {
+		return Canvas{}, fmt.Errorf("ppm: unsupported magic number %q, expected P3 or P6", magic)
+	}
+
+	width, err := ppm.number("width", 1, maxDecodePixels)
+	if err != nil {
+		return Canvas{}, err
+	}
+
+	height, err := ppm.number("height", 1, maxDecodePixels)
+	if err != nil {
+		return Canvas{}, err
+	}
+
+	if width*height > maxDecodePixels {
+		return Canvas{}, fmt.Errorf("ppm: image of %dx%d pixels is too large", width, height)
+	}
+
+	maxValue, err := ppm.number("maxval", 1, 65535)
+	if err != nil {
+		return Canvas{}, err
+	}
+
+	var rows [][]Color
+
+	if magic == "P3" {
+		rows, err = ppm.readText(uint(width), uint(height), maxValue)
+	} else {
+		rows, err = ppm.readBinary(uint(width), uint(height), maxValue)
+	}
+
+	if err != nil {
+		return Canvas{}, err
+	}
+
+	return canvasFromRows(uint(width), rows), nil
+}
+
+func canvasFromRows(width uint, rows [][]Color) Canvas {
+	canvas := NewCanvas(width, uint(len(rows)))
+
+	for y, row := range rows {
+		for x, color := range row {
+			canvas.WritePixel(uint(x), uint(y), color)
+		}
+	}
+
+	return canvas
+}
+
+type ppmReader struct {
+	reader *bufio.Reader
+	// bytes consumed so far
+	offset int
+	// offset of the first byte of the last token, used in errors
+	start int
+}
+
+func (ppm *ppmReader) readByte() (byte, error) {
+	b, err := ppm.reader.ReadByte()
+	if err == nil {
+		ppm.offset++
+	}
+
+	return b, err
+}
+
+// next whitespace separated token, skipping comments that run from # to
+// the end of the line
+func (ppm *ppmReader) token(what string) (string, error) {
+	token := []byte{}
+
+	for {
+		b, err := ppm.readByte()
+		if err == io.EOF {
+			if len(token) > 0 {
+				return string(token), nil
+			}
+
+			return "", fmt.Errorf("ppm: unexpected end of file reading %s", what)
+		} else if err != nil {
+			return "", fmt.Errorf("ppm: reading %s: %w", what, err)
+		}
+
+		switch {
+		case b == '#' && len(token) == 0:
+			if err := ppm.skipComment(); err != nil {
+				return "", fmt.Errorf("ppm: unexpected end of file reading %s", what)
+			}
+		case isPPMSpace(b):
+			if len(token) > 0 {
+				return string(token), nil
+			}
+		default:
+			if len(token) == 0 {
+				ppm.start = ppm.offset - 1
+			}
+
+			token = append(token, b)
+		}
+	}
+}
+
+func (ppm *ppmReader) skipComment() error {
+	for {
+		b, err := ppm.readByte()
+		if err != nil {
+			return err
+		}
+
+		if b == '\n' || b == '\r' {
+			return nil
+		}
+	}
+}
+
+func (ppm *ppmReader) number(what string, low int, high int) (int, error) {
+	token, err := ppm.token(what)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("ppm: invalid %s %q at byte %d", what, token, ppm.start)
+	}
+
+	if value < low || value > high {
+		return 0, fmt.Errorf("ppm: %s %d at byte %d is not between %d and %d", what, value, ppm.start, low, high)
+	}
+
+	return value, nil
+}
+
+// rows of pixels, each allocated once its first sample has been read
+func (ppm *ppmReader) readText(width uint, height uint, maxValue int) ([][]Color, error) {
+	var rows [][]Color
+
+	for y := uint(0); y < height; y++ {
+		var row []Color
+
+		for x := uint(0); x < width; x++ {
+			samples := [3]float64{}
+
+			for i := range samples {
+				token, err := ppm.token("pixel data")
+				if err != nil {
+					return nil, fmt.Errorf("%w, sample %d of pixel (%d, %d)", err, i+1, x, y)
+				}
+
+				value, err := strconv.Atoi(token)
+				if err != nil || value < 0 || value > maxValue {
+					return nil, fmt.Errorf("ppm: sample %d of pixel (%d, %d) at byte %d is %q, expected 0 to maxval %d",
+						i+1, x, y, ppm.start, token, maxValue)
+				}
+
+				samples[i] = float64(value) / float64(maxValue)
+			}
+
+			if row == nil {
+				row = make([]Color, width)
+			}
+
+			row[x] = NewColor(samples[0], samples[1], samples[2])
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// rows of pixels, each allocated once its bytes have been read
+func (ppm *ppmReader) readBinary(width uint, height uint, maxValue int) ([][]Color, error) {
+	bytesPerSample := 1
+	if maxValue > 255 {
+		bytesPerSample = 2
+	}
+
+	row := make([]byte, int(width)*3*bytesPerSample)
+	var rows [][]Color
+
+	for y := uint(0); y < height; y++ {
+		read, err := io.ReadFull(ppm.reader, row)
+		ppm.offset += read
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("ppm: unexpected end of file in row %d of %d", y, height)
+		} else if err != nil {
+			return nil, fmt.Errorf("ppm: reading row %d: %w", y, err)
+		}
+
+		samples := [3]float64{}
+		colors := make([]Color, width)
+
+		for x := uint(0); x < width; x++ {
+			for i := range samples {
+				start := (int(x)*3 + i) * bytesPerSample
+
+				value := int(row[start])
+				if bytesPerSample == 2 {
+					value = value<<8 | int(row[start+1])
+				}
+
+				if value > maxValue {
+					return nil, fmt.Errorf("ppm: sample %d of pixel (%d, %d) is %d, above maxval %d", i+1, x, y, value, maxValue)
+				}
+
+				samples[i] = float64(value) / float64(maxValue)
+			}
+
+			colors[x] = NewColor(samples[0], samples[1], samples[2])
+		}
+
+		rows = append(rows, colors)
+	}
+
+	return rows, nil
+}
+
+func isPPMSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\v' || b == '\f'
+}
+
+func ReadPNG(reader io.Reader) (Canvas, error) {
+	// the header is read twice, once to check the size before decoding
+	var header bytes.Buffer
+	config, err := png.DecodeConfig(io.TeeReader(reader, &header))
+	if err != nil {
+		return Canvas{}, fmt.Errorf("png: %w", err)
+	}
+
+	if config.Width*config.Height > maxDecodePixels {
+		return Canvas{}, fmt.Errorf("png: image of %dx%d pixels is too large", config.Width, config.Height)
+	}
+
+	decoded, err := png.Decode(io.MultiReader(&header, reader))
+	if err != nil {
+		return Canvas{}, fmt.Errorf("png: %w", err)
+	}
+
+	return FromImage(decoded), nil
+}
+
+// canvas with the colors of an image, ignoring transparency
+func FromImage(source stdimage.Image) Canvas {
+	bounds := source.Bounds()
+	canvas := NewCanvas(uint(bounds.Dx()), uint(bounds.Dy()))
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			canvas.WritePixel(uint(x-bounds.Min.X), uint(y-bounds.Min.Y), opaqueColor(source.At(x, y)))
+		}
+	}
+
+	return canvas
+}
+
+// color without alpha, non premultiplied colors keep their values even
+// where they are fully transparent
+func opaqueColor(c color.Color) Color {
+	var pixel color.NRGBA64
+
+	switch c := c.(type) {
+	case color.NRGBA:
+		pixel = color.NRGBA64{uint16(c.R) * 0x101, uint16(c.G) * 0x101, uint16(c.B) * 0x101, 0xffff}
+	case color.NRGBA64:
+		pixel = c
+	default:
+		pixel = color.NRGBA64Model.Convert(c).(color.NRGBA64)
+	}
+
+	return NewColor(float64(pixel.R)/65535, float64(pixel.G)/65535, float64(pixel.B)/65535)
+}
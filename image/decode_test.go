@@ -0,0 +1,203 @@
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	. "go-raytracer/core"
+	"hash/crc32"
+	stdimage "image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func EqualColor(t *testing.T, expected Color, actual Color) {
+	if expected.Equals(actual) {
+		assert.True(t, true)
+	} else {
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestReadPPMText(t *testing.T) {
+	data := `P3
+# a comment before the size
+4 3
+255
+255 127 0  0 0 0  0 0 0  0 0 0
+0 0 0  0 0 0  0 0 0  0 0 0 # a comment in the pixel data
+0 0 0  0 0 0  0 0 0  0 51 255`
+
+	canvas, err := ReadPPM(strings.NewReader(data))
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), canvas.Width())
+	assert.Equal(t, uint(3), canvas.Height())
+	EqualColor(t, NewColor(1, 127.0/255, 0), canvas.Pixel[0][0])
+	EqualColor(t, NewColor(0, 0.2, 1), canvas.Pixel[3][2])
+	EqualColor(t, Black, canvas.Pixel[1][1])
+}
+
+func TestReadPPMScalesByMaxValue(t *testing.T) {
+	canvas, err := ReadPPM(strings.NewReader("P3 2 1 100\n100 50 0 25 75 10\n"))
+
+	assert.NoError(t, err)
+	EqualColor(t, NewColor(1, 0.5, 0), canvas.Pixel[0][0])
+	EqualColor(t, NewColor(0.25, 0.75, 0.1), canvas.Pixel[1][0])
+}
+
+func TestReadPPMBinary(t *testing.T) {
+	canvas, err := ReadPPM(strings.NewReader("P6\n# comment\n2 1\n255\n\xff\x00\x33\x00\x80\xff"))
+
+	assert.NoError(t, err)
+	EqualColor(t, NewColor(1, 0, 0.2), canvas.Pixel[0][0])
+	EqualColor(t, NewColor(0, 128.0/255, 1), canvas.Pixel[1][0])
+}
+
+func TestReadPPMBinary16Bit(t *testing.T) {
+	canvas, err := ReadPPM(strings.NewReader("P6 1 1 65535\n\xff\xff\x80\x00\x00\x00"))
+
+	assert.NoError(t, err)
+	EqualColor(t, NewColor(1, 32768.0/65535, 0), canvas.Pixel[0][0])
+}
+
+func TestReadPPMRoundTrip(t *testing.T) {
+	canvas := NewCanvas(7, 5)
+	for x := uint(0); x < 7; x++ {
+		for y := uint(0); y < 5; y++ {
+			canvas.WritePixel(x, y, NewColor(float64(x)/7, float64(y)/5, 0.5))
+		}
+	}
+
+	for _, options := range []PPMOptions{{}, {Binary: true}, {Binary: true, MaxValue: 65535}, {MaxValue: 1000}} {
+		data := bytes.Buffer{}
+		assert.NoError(t, canvas.WritePPM(&data, options))
+
+		read, err := ReadPPM(&data)
+		assert.NoError(t, err)
+
+		written := bytes.Buffer{}
+		assert.NoError(t, read.WritePPM(&written, options))
+
+		expected := bytes.Buffer{}
+		canvas.WritePPM(&expected, options)
+		assert.Equal(t, expected.String(), written.String(), options)
+	}
+}
+
+func TestReadPPMErrors(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{"", "ppm: unexpected end of file reading magic number"},
+		{"P5 1 1 255\n\x00", `ppm: unsupported magic number "P5", expected P3 or P6`},
+		{"P3 wide 1 255", `ppm: invalid width "wide" at byte 3`},
+		{"P3 0 1 255", "ppm: width 0 at byte 3 is not between 1 and 33554432"},
+		{"P3 100000 100000 255", "ppm: image of 100000x100000 pixels is too large"},
+		{"P3 1 1 70000", "ppm: maxval 70000 at byte 7 is not between 1 and 65535"},
+		{"P3 1 1 # comment without end", "ppm: unexpected end of file reading maxval"},
+		{"P3 1 1 255\n255 0", "ppm: unexpected end of file reading pixel data, sample 3 of pixel (0, 0)"},
+		{"P3 1 1 100\n0 0 101\n", `ppm: sample 3 of pixel (0, 0) at byte 15 is "101", expected 0 to maxval 100`},
+		{"P3 1 1 100\n0 -1 0\n", `ppm: sample 2 of pixel (0, 0) at byte 13 is "-1", expected 0 to maxval 100`},
+		{"P6 2 2 255\n\x00\x00\x00\x00\x00\x00\x00", "ppm: unexpected end of file in row 1 of 2"},
+		{"P6 5792 5792 255\n\x00\x00\x00", "ppm: unexpected end of file in row 0 of 5792"},
+		{"P6 1 1 100\n\x00\x65\x00", "ppm: sample 2 of pixel (0, 0) is 101, above maxval 100"},
+	}
+
+	for _, test := range tests {
+		_, err := ReadPPM(strings.NewReader(test.data))
+
+		assert.EqualError(t, err, test.expected, test.data)
+	}
+}
+
+func TestReadPNG(t *testing.T) {
+	source := stdimage.NewNRGBA(stdimage.Rect(0, 0, 3, 2))
+	source.SetNRGBA(2, 1, color.NRGBA{255, 51, 0, 255})
+
+	data := bytes.Buffer{}
+	assert.NoError(t, png.Encode(&data, source))
+
+	canvas, err := ReadPNG(&data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), canvas.Width())
+	assert.Equal(t, uint(2), canvas.Height())
+	EqualColor(t, NewColor(1, 0.2, 0), canvas.Pixel[2][1])
+	EqualColor(t, Black, canvas.Pixel[0][0])
+}
+
+func TestReadPNGErrors(t *testing.T) {
+	_, err := ReadPNG(strings.NewReader("\x89PNG\r\n\x1a\n"))
+
+	assert.EqualError(t, err, "png: unexpected EOF")
+
+	// a header claiming more pixels than the limit, without any pixel data
+	header := make([]byte, 13)
+	binary.BigEndian.PutUint32(header[0:], 100000)
+	binary.BigEndian.PutUint32(header[4:], 100000)
+	header[8], header[9] = 8, 2
+
+	data := bytes.Buffer{}
+	data.WriteString("\x89PNG\r\n\x1a\n")
+	binary.Write(&data, binary.BigEndian, uint32(len(header)))
+	data.WriteString("IHDR")
+	data.Write(header)
+	binary.Write(&data, binary.BigEndian, crc32.ChecksumIEEE(append([]byte("IHDR"), header...)))
+
+	_, err = ReadPNG(&data)
+
+	assert.EqualError(t, err, "png: image of 100000x100000 pixels is too large")
+}
+
+func TestFromImageIgnoresAlpha(t *testing.T) {
+	source := stdimage.NewNRGBA(stdimage.Rect(10, 20, 11, 21))
+	source.SetNRGBA(10, 20, color.NRGBA{0, 255, 0, 0})
+
+	canvas := FromImage(source)
+
+	EqualColor(t, Green, canvas.Pixel[0][0])
+}
+
+func TestDecodeDetectsFormat(t *testing.T) {
+	canvas := NewCanvas(2, 2)
+	canvas.WritePixel(1, 0, NewColor(1, 0, 1))
+
+	for _, format := range []Format{FormatPPM, FormatPNG} {
+		data := bytes.Buffer{}
+		assert.NoError(t, canvas.Encode(&data, EncodeOptions{Format: format}))
+
+		decoded, err := Decode(&data)
+
+		assert.NoError(t, err)
+		EqualColor(t, NewColor(1, 0, 1), decoded.Pixel[1][0])
+	}
+
+	_, err := Decode(strings.NewReader("GIF89a"))
+	assert.EqualError(t, err, "image is not a PPM or PNG file")
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	canvas := NewCanvas(2, 1)
+	canvas.WritePixel(0, 0, White)
+
+	path := filepath.Join(dir, "image.png")
+	assert.NoError(t, canvas.SaveFile(path, 0))
+
+	loaded, err := LoadFile(path)
+	assert.NoError(t, err)
+	EqualColor(t, White, loaded.Pixel[0][0])
+
+	broken := filepath.Join(dir, "broken.ppm")
+	assert.NoError(t, os.WriteFile(broken, []byte("P3 1 1 255\n"), 0666))
+
+	_, err = LoadFile(broken)
+	assert.EqualError(t, err, broken+": ppm: unexpected end of file reading pixel data, sample 1 of pixel (0, 0)")
+}
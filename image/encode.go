@@ -0,0 +1,128 @@
+package image
+
+import (
+	"fmt"
+	stdimage "image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Format int
+
+const (
+	FormatPPM Format = iota
+	FormatPNG
+	FormatJPEG
+)
+
+const DefaultJPEGQuality = 90
+
+type EncodeOptions struct {
+	Format Format
+	// jpeg quality from 1 to 100, 0 uses DefaultJPEGQuality
+	Quality int
+	PPM     PPMOptions
+}
+
+func (format Format) String() string {
+	switch format {
+	case FormatPPM:
+		return "ppm"
+	case FormatPNG:
+		return "png"
+	case FormatJPEG:
+		return "jpeg"
+	}
+
+	return fmt.Sprintf("Format(%d)", int(format))
+}
+
+// format named ppm, png, jpeg or jpg, in any case
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "ppm":
+		return FormatPPM, nil
+	case "png":
+		return FormatPNG, nil
+	case "jpeg", "jpg":
+		return FormatJPEG, nil
+	}
+
+	return 0, fmt.Errorf("unsupported image format %q", name)
+}
+
+// format matching the extension of path
+func FormatForPath(path string) (Format, error) {
+	extension := filepath.Ext(path)
+	if extension == "" {
+		return 0, fmt.Errorf("%s has no extension to pick an image format from", path)
+	}
+
+	return ParseFormat(strings.TrimPrefix(extension, "."))
+}
+
+// copy of the canvas as 8 bit color, clamped and scaled the same way as ToPPM
+func (canvas Canvas) ToImage() *stdimage.NRGBA {
+	result := stdimage.NewNRGBA(stdimage.Rect(0, 0, int(canvas.width), int(canvas.height)))
+
+	for y := uint(0); y < canvas.height; y++ {
+		for x := uint(0); x < canvas.width; x++ {
+			pixel := canvas.Pixel[x][y]
+
+			result.SetNRGBA(int(x), int(y), color.NRGBA{
+				uint8(scaleFloat(pixel.Red)),
+				uint8(scaleFloat(pixel.Green)),
+				uint8(scaleFloat(pixel.Blue)),
+				255})
+		}
+	}
+
+	return result
+}
+
+func (canvas Canvas) Encode(writer io.Writer, options EncodeOptions) error {
+	switch options.Format {
+	case FormatPPM:
+		return canvas.WritePPM(writer, options.PPM)
+	case FormatPNG:
+		return png.Encode(writer, canvas.ToImage())
+	case FormatJPEG:
+		quality := options.Quality
+		if quality == 0 {
+			quality = DefaultJPEGQuality
+		}
+
+		if quality < 1 || quality > 100 {
+			return fmt.Errorf("jpeg quality %d is not between 1 and 100", quality)
+		}
+
+		return jpeg.Encode(writer, canvas.ToImage(), &jpeg.Options{Quality: quality})
+	}
+
+	return fmt.Errorf("unsupported image format %s", options.Format)
+}
+
+// writes the canvas in the format matching the extension of path
+func (canvas Canvas) SaveFile(path string, quality int) error {
+	format, err := FormatForPath(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = canvas.Encode(file, EncodeOptions{Format: format, Quality: quality})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
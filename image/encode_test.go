@@ -0,0 +1,129 @@
+package image
+
+import (
+	"bytes"
+	. "go-raytracer/core"
+	stdimage "image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := map[string]Format{"ppm": FormatPPM, "PNG": FormatPNG, "jpeg": FormatJPEG, "jpg": FormatJPEG}
+
+	for name, expected := range tests {
+		format, err := ParseFormat(name)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, format)
+	}
+
+	_, err := ParseFormat("tiff")
+	assert.EqualError(t, err, `unsupported image format "tiff"`)
+}
+
+func TestFormatForPath(t *testing.T) {
+	format, err := FormatForPath("render/scene.JPG")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatJPEG, format)
+
+	_, err = FormatForPath("render/scene")
+	assert.EqualError(t, err, "render/scene has no extension to pick an image format from")
+}
+
+func TestToImageMatchesPPMScaling(t *testing.T) {
+	canvas := NewCanvas(3, 2)
+	canvas.WritePixel(0, 0, NewColor(1.5, 0, 0))
+	canvas.WritePixel(1, 0, NewColor(0, 0.5, 0))
+	canvas.WritePixel(2, 1, NewColor(-0.5, 0, 1))
+
+	result := canvas.ToImage()
+
+	assert.Equal(t, stdimage.Rect(0, 0, 3, 2), result.Bounds())
+	assert.Equal(t, color.NRGBA{255, 0, 0, 255}, result.NRGBAAt(0, 0))
+	assert.Equal(t, color.NRGBA{0, 128, 0, 255}, result.NRGBAAt(1, 0))
+	assert.Equal(t, color.NRGBA{0, 0, 255, 255}, result.NRGBAAt(2, 1))
+	assert.Equal(t, color.NRGBA{0, 0, 0, 255}, result.NRGBAAt(0, 1))
+}
+
+func TestEncodePNG(t *testing.T) {
+	canvas := NewCanvas(4, 3)
+	canvas.WritePixel(1, 2, NewColor(0.25, 0.5, 0.75))
+
+	data := bytes.Buffer{}
+	assert.NoError(t, canvas.Encode(&data, EncodeOptions{Format: FormatPNG}))
+
+	decoded, err := png.Decode(&data)
+	assert.NoError(t, err)
+	assert.Equal(t, stdimage.Rect(0, 0, 4, 3), decoded.Bounds())
+
+	r, g, b, a := decoded.At(1, 2).RGBA()
+	assert.Equal(t, []uint32{64, 128, 192, 255}, []uint32{r >> 8, g >> 8, b >> 8, a >> 8})
+}
+
+func TestEncodeJPEGQuality(t *testing.T) {
+	canvas := NewCanvas(32, 32)
+	for x := uint(0); x < 32; x++ {
+		for y := uint(0); y < 32; y++ {
+			canvas.WritePixel(x, y, NewColor(float64(x)/32, float64(y)/32, float64(x^y)/32))
+		}
+	}
+
+	low := bytes.Buffer{}
+	high := bytes.Buffer{}
+	assert.NoError(t, canvas.Encode(&low, EncodeOptions{Format: FormatJPEG, Quality: 10}))
+	assert.NoError(t, canvas.Encode(&high, EncodeOptions{Format: FormatJPEG, Quality: 100}))
+
+	assert.Less(t, low.Len(), high.Len())
+
+	decoded, err := jpeg.Decode(&high)
+	assert.NoError(t, err)
+	assert.Equal(t, stdimage.Rect(0, 0, 32, 32), decoded.Bounds())
+}
+
+func TestEncodeJPEGInvalidQuality(t *testing.T) {
+	canvas := NewCanvas(1, 1)
+
+	err := canvas.Encode(&bytes.Buffer{}, EncodeOptions{Format: FormatJPEG, Quality: 101})
+
+	assert.EqualError(t, err, "jpeg quality 101 is not between 1 and 100")
+}
+
+func TestEncodePPM(t *testing.T) {
+	canvas := NewCanvas(5, 3)
+	canvas.WritePixel(0, 0, NewColor(1.5, 0, 0))
+
+	data := bytes.Buffer{}
+	assert.NoError(t, canvas.Encode(&data, EncodeOptions{Format: FormatPPM}))
+
+	assert.Equal(t, canvas.ToPPM(), data.String())
+}
+
+func TestSaveFilePicksFormatFromExtension(t *testing.T) {
+	canvas := NewCanvas(2, 2)
+	dir := t.TempDir()
+
+	for _, name := range []string{"image.png", "image.jpg", "image.ppm"} {
+		assert.NoError(t, canvas.SaveFile(filepath.Join(dir, name), 0))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "image.png"))
+	assert.NoError(t, err)
+	assert.Equal(t, "\x89PNG", string(data[:4]))
+
+	data, err = os.ReadFile(filepath.Join(dir, "image.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "\xff\xd8", string(data[:2]))
+
+	data, err = os.ReadFile(filepath.Join(dir, "image.ppm"))
+	assert.NoError(t, err)
+	assert.Equal(t, canvas.ToPPM(), string(data))
+
+	assert.Error(t, canvas.SaveFile(filepath.Join(dir, "image.gif"), 0))
+}
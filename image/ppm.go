@@ -0,0 +1,108 @@
+package image
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+const ppmMaxCharPerLine = 70
+
+type PPMOptions struct {
+	// writes P6 with raw bytes instead of P3 with decimal text
+	Binary bool
+	// largest sample value from 1 to 65535, 0 means 255, values above 255
+	// take two bytes per sample in P6
+	MaxValue int
+}
+
+// streams the canvas as PPM, one row at a time
+func (canvas Canvas) WritePPM(writer io.Writer, options PPMOptions) error {
+	maxValue := options.MaxValue
+	if maxValue == 0 {
+		maxValue = 255
+	}
+
+	if maxValue < 1 || maxValue > 65535 {
+		return fmt.Errorf("ppm maxval %d is not between 1 and 65535", maxValue)
+	}
+
+	buffered := bufio.NewWriter(writer)
+
+	magic := "P3"
+	if options.Binary {
+		magic = "P6"
+	}
+
+	fmt.Fprintf(buffered, "%s\n%d %d\n%d\n", magic, canvas.width, canvas.height, maxValue)
+
+	if options.Binary {
+		canvas.writePPMBinary(buffered, maxValue)
+	} else {
+		canvas.writePPMText(buffered, maxValue)
+	}
+
+	return buffered.Flush()
+}
+
+func (canvas Canvas) writePPMText(writer *bufio.Writer, maxValue int) {
+	// room for a separator and the widest sample, plus one spare
+	reserve := len(strconv.Itoa(maxValue)) + 2
+	line := make([]byte, 0, ppmMaxCharPerLine+1)
+
+	for y := uint(0); y < canvas.height; y++ {
+		for x := uint(0); x < canvas.width; x++ {
+			pixel := canvas.Pixel[x][y]
+
+			for _, sample := range []float64{pixel.Red, pixel.Green, pixel.Blue} {
+				if len(line)+reserve > ppmMaxCharPerLine {
+					writer.Write(append(line, '\n'))
+					line = line[:0]
+				}
+
+				if len(line) != 0 {
+					line = append(line, ' ')
+				}
+
+				line = strconv.AppendInt(line, int64(scaleTo(sample, maxValue)), 10)
+			}
+		}
+
+		writer.Write(append(line, '\n'))
+		line = line[:0]
+	}
+}
+
+func (canvas Canvas) writePPMBinary(writer *bufio.Writer, maxValue int) {
+	for y := uint(0); y < canvas.height; y++ {
+		for x := uint(0); x < canvas.width; x++ {
+			pixel := canvas.Pixel[x][y]
+
+			for _, sample := range []float64{pixel.Red, pixel.Green, pixel.Blue} {
+				value := scaleTo(sample, maxValue)
+
+				if maxValue > 255 {
+					writer.WriteByte(byte(value >> 8))
+				}
+
+				writer.WriteByte(byte(value))
+			}
+		}
+	}
+}
+
+func (canvas Canvas) SavePPM(path string, options PPMOptions) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = canvas.WritePPM(file, options)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
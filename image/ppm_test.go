@@ -0,0 +1,110 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	. "go-raytracer/core"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(data []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWritePPMText(t *testing.T) {
+	canvas := NewCanvas(5, 3)
+	canvas.WritePixel(0, 0, NewColor(1.5, 0, 0))
+	canvas.WritePixel(2, 1, NewColor(0, 0.5, 0))
+	canvas.WritePixel(4, 2, NewColor(-0.5, 0, 1))
+
+	data := bytes.Buffer{}
+	assert.NoError(t, canvas.WritePPM(&data, PPMOptions{}))
+
+	assert.Equal(t, "P3\n5 3\n255\n"+
+		"255 0 0 0 0 0 0 0 0 0 0 0 0 0 0\n"+
+		"0 0 0 0 0 0 0 128 0 0 0 0 0 0 0\n"+
+		"0 0 0 0 0 0 0 0 0 0 0 0 0 0 255\n", data.String())
+}
+
+func TestWritePPMBinary(t *testing.T) {
+	canvas := NewCanvas(2, 1)
+	canvas.WritePixel(0, 0, NewColor(1.5, 0, 0.5))
+	canvas.WritePixel(1, 0, NewColor(0, 0.25, 1))
+
+	data := bytes.Buffer{}
+	assert.NoError(t, canvas.WritePPM(&data, PPMOptions{Binary: true}))
+
+	assert.Equal(t, "P6\n2 1\n255\n\xff\x00\x80\x00\x40\xff", data.String())
+}
+
+func TestWritePPMBinary16Bit(t *testing.T) {
+	canvas := NewCanvas(1, 1)
+	canvas.WritePixel(0, 0, NewColor(1, 0.5, 0))
+
+	data := bytes.Buffer{}
+	assert.NoError(t, canvas.WritePPM(&data, PPMOptions{Binary: true, MaxValue: 65535}))
+
+	assert.Equal(t, "P6\n1 1\n65535\n\xff\xff\x80\x00\x00\x00", data.String())
+}
+
+func TestWritePPMText16BitKeepsLinesShort(t *testing.T) {
+	canvas := NewCanvas(10, 2)
+	for y := uint(0); y < 2; y++ {
+		for x := uint(0); x < 10; x++ {
+			canvas.WritePixel(x, y, NewColor(1, 0.8, 0.6))
+		}
+	}
+
+	data := bytes.Buffer{}
+	assert.NoError(t, canvas.WritePPM(&data, PPMOptions{MaxValue: 65535}))
+
+	lines := strings.Split(data.String(), "\n")
+	assert.Equal(t, "65535", lines[2])
+	assert.Equal(t, "65535 52428 39321 65535 52428 39321 65535 52428 39321 65535 52428", lines[3])
+
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), 70)
+	}
+}
+
+func TestWritePPMInvalidMaxValue(t *testing.T) {
+	canvas := NewCanvas(1, 1)
+
+	err := canvas.WritePPM(&bytes.Buffer{}, PPMOptions{MaxValue: 65536})
+
+	assert.EqualError(t, err, "ppm maxval 65536 is not between 1 and 65535")
+}
+
+func TestWritePPMReportsWriteErrors(t *testing.T) {
+	canvas := NewCanvas(100, 100)
+
+	err := canvas.WritePPM(failingWriter{}, PPMOptions{Binary: true})
+
+	assert.EqualError(t, err, "disk full")
+}
+
+func TestSavePPM(t *testing.T) {
+	canvas := NewCanvas(3, 2)
+	canvas.WritePixel(1, 1, NewColor(0.5, 0.5, 0.5))
+	path := filepath.Join(t.TempDir(), "image.ppm")
+
+	assert.NoError(t, canvas.SavePPM(path, PPMOptions{}))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, canvas.ToPPM(), string(data))
+}
+
+func TestScaleTo(t *testing.T) {
+	assert.Equal(t, 0, scaleTo(-1, 65535))
+	assert.Equal(t, 65535, scaleTo(1.2, 65535))
+	assert.Equal(t, 32768, scaleTo(0.5, 65535))
+	assert.Equal(t, 1, scaleTo(0.5, 1))
+}
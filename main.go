@@ -1,106 +1,378 @@
 package main
 
 import (
+	"errors"
 	"flag"
-	. "go-raytracer/core"
+	"fmt"
 	. "go-raytracer/geometry"
+	"go-raytracer/image"
 	. "go-raytracer/physics"
-	"log"
-	"math"
+	"go-raytracer/scene"
+	"io"
 	"os"
 	"runtime"
 	"runtime/pprof"
 )
 
-var cpuprofile = flag.String("cpuprofile", "", "write cpu profile to `file`")
-var memprofile = flag.String("memprofile", "", "write memory profile to `file`")
+// exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+const usage = `usage: go-raytracer <command> [flags]
+
+commands:
+  render    render a scene to an image
+  validate  check a scene file for errors
+  info      describe the contents of a scene file
+
+run go-raytracer <command> -h for the flags of a command
+`
 
 func main() {
-	generateScene()
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "render":
+		return render(args[1:], stdout, stderr)
+	case "validate":
+		return validate(args[1:], stdout, stderr)
+	case "info":
+		return info(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+	return exitUsage
+}
+
+type renderOptions struct {
+	scene       string
+	width       uint
+	height      uint
+	fieldOfView float64
+	depth       uint
+	glossy      uint
+	samples     uint
+	sampler     string
+	integrator  string
+	seed        uint64
+	adaptive    float64
+	threads     int
+	output      string
+	format      string
+	quality     int
+	encode      image.EncodeOptions
+	cpuprofile  string
+	memprofile  string
+}
+
+func render(args []string, stdout io.Writer, stderr io.Writer) int {
+	options := renderOptions{}
+
+	flags := newFlagSet("render", stderr)
+	flags.StringVar(&options.scene, "scene", "scenes/scene.yml", "scene `file` to render")
+	flags.UintVar(&options.width, "width", 0, "image width in pixels, 0 uses the scene's camera")
+	flags.UintVar(&options.height, "height", 0, "image height in pixels, 0 uses the scene's camera")
+	flags.Float64Var(&options.fieldOfView, "fov", 0, "field of view in radians, 0 uses the scene's camera")
+	flags.UintVar(&options.depth, "depth", 4, "maximum recursion depth for reflection and refraction")
+	flags.UintVar(&options.samples, "samples", 1, "rays per pixel")
+	flags.UintVar(&options.glossy, "glossy-samples", DefaultGlossySamples, "rays a glossy reflection or rough refraction is spread over")
+	flags.StringVar(&options.sampler, "sampler", "stratified", "placement of samples within a pixel, stratified or jittered")
+	flags.StringVar(&options.integrator, "integrator", "whitted", "how light is computed, whitted ray tracing or path tracing with path")
+	flags.Uint64Var(&options.seed, "seed", 0, "seed for jittered samples and paths, renders with the same seed are identical")
+	flags.Float64Var(&options.adaptive, "adaptive", 0, "only supersample pixels that differ from a neighbor by more than this, 0 samples every pixel")
+	flags.IntVar(&options.threads, "threads", runtime.NumCPU(), "number of rendering threads")
+	flags.StringVar(&options.output, "output", "render/scene.ppm", "image `file` to write")
+	flags.StringVar(&options.format, "format", "", "image format (ppm, png or jpeg), by default from the output file extension")
+	flags.IntVar(&options.quality, "quality", image.DefaultJPEGQuality, "jpeg quality from 1 to 100")
+	flags.BoolVar(&options.encode.PPM.Binary, "ppm-binary", false, "write binary P6 instead of text P3 ppm")
+	flags.IntVar(&options.encode.PPM.MaxValue, "ppm-maxval", 255, "largest ppm sample value, up to 65535 for 16 bit samples")
+	flags.StringVar(&options.cpuprofile, "cpuprofile", "", "write cpu profile to `file`")
+	flags.StringVar(&options.memprofile, "memprofile", "", "write memory profile to `file`")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if flags.NArg() != 0 {
+		fmt.Fprintf(stderr, "render: unexpected argument %q\n", flags.Arg(0))
+		return exitUsage
+	}
+
+	if err := options.check(); err != nil {
+		fmt.Fprintf(stderr, "render: %s\n", err)
+		return exitUsage
+	}
+
+	if err := options.render(stdout); err != nil {
+		fmt.Fprintf(stderr, "render: %s\n", err)
+		return exitError
+	}
+
+	return exitOK
 }
 
-func generateScene() {
-	flag.Parse()
-	if *cpuprofile != "" {
-		f, err := os.Create(*cpuprofile)
+func (options *renderOptions) check() error {
+	if options.samples == 0 {
+		return errors.New("samples must be at least 1")
+	}
+
+	if options.glossy == 0 {
+		return errors.New("glossy-samples must be at least 1")
+	}
+
+	if options.sampler != "stratified" && options.sampler != "jittered" {
+		return fmt.Errorf("unknown sampler %q, expected stratified or jittered", options.sampler)
+	}
+
+	if options.integrator != "whitted" && options.integrator != "path" {
+		return fmt.Errorf("unknown integrator %q, expected whitted or path", options.integrator)
+	}
+
+	if options.adaptive < 0 {
+		return errors.New("adaptive must not be negative")
+	}
+
+	if options.threads < 1 {
+		return errors.New("threads must be at least 1")
+	}
+
+	if options.fieldOfView < 0 {
+		return errors.New("fov must be positive")
+	}
+
+	if options.quality < 1 || options.quality > 100 {
+		return errors.New("quality must be between 1 and 100")
+	}
+
+	if options.encode.PPM.MaxValue < 1 || options.encode.PPM.MaxValue > 65535 {
+		return errors.New("ppm-maxval must be between 1 and 65535")
+	}
+
+	var err error
+	if options.format == "" {
+		options.encode.Format, err = image.FormatForPath(options.output)
+	} else {
+		options.encode.Format, err = image.ParseFormat(options.format)
+	}
+
+	options.encode.Quality = options.quality
+
+	return err
+}
+
+func (options *renderOptions) render(stdout io.Writer) error {
+	loaded, err := scene.LoadFile(options.scene)
+	if err != nil {
+		return err
+	}
+
+	camera := loaded.Camera
+	if options.width != 0 || options.height != 0 || options.fieldOfView != 0 {
+		width, height, fieldOfView := camera.HSize(), camera.VSize(), camera.FieldOfView()
+
+		if options.width != 0 {
+			width = options.width
+		}
+
+		if options.height != 0 {
+			height = options.height
+		}
+
+		if options.fieldOfView != 0 {
+			fieldOfView = options.fieldOfView
+		}
+
+		camera.SetView(width, height, fieldOfView)
+	}
+
+	camera.MaxDepth = options.depth
+	loaded.World.GlossySamples = options.glossy
+	camera.Samples = options.samples
+	camera.Seed = options.seed
+	camera.AdaptiveThreshold = options.adaptive
+
+	if options.sampler == "jittered" {
+		camera.SampleMode = SampleJittered
+	}
+
+	if options.integrator == "path" {
+		camera.Integrator = NewPathTracer()
+	}
+
+	camera.Workers = options.threads
+
+	if options.cpuprofile != "" {
+		file, err := os.Create(options.cpuprofile)
 		if err != nil {
-			log.Fatal("could not create CPU profile: ", err)
+			return fmt.Errorf("could not create CPU profile: %w", err)
 		}
-		defer f.Close()
-		if err := pprof.StartCPUProfile(f); err != nil {
-			log.Fatal("could not start CPU profile: ", err)
+		defer file.Close()
+
+		if err := pprof.StartCPUProfile(file); err != nil {
+			return fmt.Errorf("could not start CPU profile: %w", err)
 		}
 		defer pprof.StopCPUProfile()
 	}
 
-	floor := NewPlane()
-	floor.Transform = floor.Transform.Translate(1, 1, -2)
-	floor.Material.Color = Blue
-	floor.Material.Ambient = 0.2
-	floor.Material.Pattern = NewCheckersPattern(
-		NewSolidPattern(Black), NewGradientPattern(
-			NewSolidPattern(Green),
-			NewSolidPattern(Blue)))
-	floor.Material.Reflective = 0.5
-	floor.Material.Transparency = 0.2
-	floor.Material.RefractiveIndex = 4.5
-
-	middleSphere := NewSphere()
-	middleSphere.Transform = middleSphere.Transform.
-		Translate(-0.5, 1.5, 0.5).
-		Scale(0.8, 0.8, 0.8)
-	middleSphere.Material.Color = NewColor(0.1, 1, 0.1)
-	middleSphere.Material.Diffuse = 0.7
-	middleSphere.Material.Specular = 0.3
-	middleSphere.Material.Ambient = 0.3
-	middleSphere.Material.Transparency = 0.5
-	middleSphere.Material.RefractiveIndex = 0
-	middleSpherePattern := NewGradientPattern(
-		NewSolidPattern(Red), NewSolidPattern(Blue))
-	middleSpherePattern.Transform = middleSpherePattern.Transform.
-		RotateX(math.Pi / 4).
-		RotateY(math.Pi / 4).
-		RotateZ(math.Pi)
-	middleSphere.Material.Pattern = middleSpherePattern
-
-	rightSphere := NewSphere()
-	rightSphere.Transform = rightSphere.Transform.
-		Translate(1.5, 1.5, -0.5).
-		Scale(0.5, 0.5, 0.5)
-	rightSphere.Material.Color = NewColor(1, 0.2, 1)
-	rightSphere.Material.Diffuse = 0.7
-	rightSphere.Material.Specular = 0.3
-	rightSphere.Material.Reflective = 0.5
-	rightSphere.Material.Transparency = 0.5
-	rightSphere.Material.RefractiveIndex = 1.5
-	rightSpherePattern := NewGradientPattern(
-		NewSolidPattern(Red), NewSolidPattern(Blue))
-	rightSpherePattern.Transform = rightSphere.Transform.
-		RotateX(math.Pi / 2).
-		RotateZ(math.Pi / 4)
-	rightSphere.Material.Pattern = rightSpherePattern
-
-	world := World{}
-	world.Light = NewPointLight(NewPoint(-10, 10, -10), White)
-	world.Objects = make([]Shape, 0)
-	world.Objects = append(world.Objects, floor, middleSphere, rightSphere)
-
-	camera := NewCamera(2000, 1000, math.Pi/3)
-	camera.Transform = ViewTransform(NewPoint(0, 1.5, -5), NewPoint(0, 1, 0), NewVector(0, 1, 0))
-
-	canvas := camera.Render(world)
-
-	os.WriteFile("render/scene.ppm", []byte(canvas.ToPPM()), 0666)
-
-	if *memprofile != "" {
-		f, err := os.Create(*memprofile)
+	canvas := camera.Render(loaded.World)
+
+	if err := writeImage(canvas, options.output, options.encode); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "wrote %dx%d image to %s\n", camera.HSize(), camera.VSize(), options.output)
+
+	if options.memprofile != "" {
+		file, err := os.Create(options.memprofile)
 		if err != nil {
-			log.Fatal("could not create memory profile: ", err)
+			return fmt.Errorf("could not create memory profile: %w", err)
 		}
-		defer f.Close()
+		defer file.Close()
+
 		runtime.GC()
-		if err := pprof.WriteHeapProfile(f); err != nil {
-			log.Fatal("could not write memory profile: ", err)
+		if err := pprof.WriteHeapProfile(file); err != nil {
+			return fmt.Errorf("could not write memory profile: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func writeImage(canvas image.Canvas, path string, options image.EncodeOptions) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = canvas.Encode(file, options)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func validate(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("validate", stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: go-raytracer validate <scene file>...")
+	}
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	code := exitOK
+
+	for _, path := range flags.Args() {
+		if _, err := scene.LoadFile(path); err != nil {
+			fmt.Fprintln(stderr, err)
+			code = exitError
+			continue
 		}
+
+		fmt.Fprintf(stdout, "%s: ok\n", path)
 	}
+
+	return code
+}
+
+func info(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("info", stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: go-raytracer info <scene file>")
+	}
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	loaded, err := scene.LoadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	camera := loaded.Camera
+	bounds := NewEmptyBounds()
+	primitives := 0
+
+	for _, object := range loaded.World.Objects {
+		bounds = bounds.Merge(ParentSpaceBounds(object))
+		primitives += countPrimitives(object)
+	}
+
+	fmt.Fprintf(stdout, "camera:     %dx%d, field of view %.4g\n", camera.HSize(), camera.VSize(), camera.FieldOfView())
+	fmt.Fprintf(stdout, "lights:     %d\n", len(loaded.World.Lights))
+	fmt.Fprintf(stdout, "objects:    %d\n", len(loaded.World.Objects))
+	fmt.Fprintf(stdout, "primitives: %d\n", primitives)
+
+	if bounds.IsEmpty() {
+		fmt.Fprintln(stdout, "bounds:     empty")
+	} else if !bounds.IsFinite() {
+		fmt.Fprintln(stdout, "bounds:     infinite")
+	} else {
+		fmt.Fprintf(stdout, "bounds:     (%.4g, %.4g, %.4g) to (%.4g, %.4g, %.4g)\n",
+			bounds.Min.X, bounds.Min.Y, bounds.Min.Z, bounds.Max.X, bounds.Max.Y, bounds.Max.Z)
+	}
+
+	return exitOK
+}
+
+// number of shapes that are not groups or csg
+func countPrimitives(shape Shape) int {
+	switch shape := shape.(type) {
+	case *Group:
+		count := 0
+		for _, child := range shape.Children {
+			count += countPrimitives(child)
+		}
+		return count
+	case *CSG:
+		return countPrimitives(shape.Left) + countPrimitives(shape.Right)
+	}
+
+	return 1
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	return flags
+}
+
+// returns false with the exit code when the command should stop
+func parseFlags(flags *flag.FlagSet, args []string) (int, bool) {
+	err := flags.Parse(args)
+	if err == flag.ErrHelp {
+		return exitOK, false
+	} else if err != nil {
+		return exitUsage, false
+	}
+
+	return exitOK, true
 }
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testScene = `
+- add: camera
+  width: 20
+  height: 10
+  field-of-view: 1.0472
+  from: [0, 1.5, -5]
+  to: [0, 1, 0]
+  up: [0, 1, 0]
+- add: light
+  at: [-10, 10, -10]
+- add: group
+  children:
+    - add: sphere
+    - add: csg
+      operation: union
+      left:
+        add: cube
+      right:
+        add: sphere
+`
+
+func writeScene(t *testing.T, data string) string {
+	path := filepath.Join(t.TempDir(), "scene.yml")
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0666))
+
+	return path
+}
+
+func runCommand(args ...string) (int, string, string) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	code := run(args, &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunWithoutCommand(t *testing.T) {
+	code, _, stderr := runCommand()
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "usage:")
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	code, _, stderr := runCommand("paint")
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `unknown command "paint"`)
+}
+
+func TestValidate(t *testing.T) {
+	path := writeScene(t, testScene)
+
+	code, stdout, _ := runCommand("validate", path)
+
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, path+": ok\n", stdout)
+}
+
+func TestValidateReportsErrors(t *testing.T) {
+	path := writeScene(t, "- add: sphere\n  colour: [1, 0, 0]\n")
+
+	code, _, stderr := runCommand("validate", path)
+
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, path+":2:3: unknown key \"colour\" in sphere\n", stderr)
+}
+
+func TestValidateWithoutFile(t *testing.T) {
+	code, _, _ := runCommand("validate")
+
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestInfo(t *testing.T) {
+	path := writeScene(t, testScene)
+
+	code, stdout, _ := runCommand("info", path)
+
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, `camera:     20x10, field of view 1.047
+lights:     1
+objects:    1
+primitives: 3
+bounds:     (-1, -1, -1) to (1, 1, 1)
+`, stdout)
+}
+
+func TestRender(t *testing.T) {
+	path := writeScene(t, testScene)
+	output := filepath.Join(t.TempDir(), "image.ppm")
+
+	code, stdout, stderr := runCommand("render", "-scene", path, "-output", output,
+		"-width", "8", "-depth", "2", "-samples", "4", "-threads", "2",
+		"-sampler", "jittered", "-seed", "3", "-adaptive", "0.05", "-integrator", "path",
+		"-glossy-samples", "4")
+
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "wrote 8x10 image to "+output+"\n", stdout)
+
+	data, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "P3\n8 10\n255\n"))
+}
+
+func TestRenderPickedFormat(t *testing.T) {
+	path := writeScene(t, testScene)
+	dir := t.TempDir()
+
+	tests := []struct {
+		args   []string
+		output string
+		magic  string
+	}{
+		{[]string{}, "image.png", "\x89PNG"},
+		{[]string{"-quality", "50"}, "image.jpeg", "\xff\xd8"},
+		{[]string{"-format", "png"}, "image.out", "\x89PNG"},
+		{[]string{"-ppm-binary", "-ppm-maxval", "65535"}, "image.ppm", "P6\n20 10\n65535\n"},
+	}
+
+	for _, test := range tests {
+		output := filepath.Join(dir, test.output)
+		args := append([]string{"render", "-scene", path, "-output", output}, test.args...)
+
+		code, _, stderr := runCommand(args...)
+		assert.Equal(t, exitOK, code, stderr)
+
+		data, err := os.ReadFile(output)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(data), test.magic), test.output)
+	}
+}
+
+func TestRenderRejectsBadFlags(t *testing.T) {
+	tests := [][]string{
+		{"-samples", "0"},
+		{"-glossy-samples", "0"},
+		{"-threads", "0"},
+		{"-fov", "-1"},
+		{"-output", "image.tiff"},
+		{"-format", "bmp"},
+		{"-output", "image"},
+		{"-quality", "0"},
+		{"-sampler", "random"},
+		{"-integrator", "photons"},
+		{"-adaptive", "-1"},
+		{"-ppm-maxval", "70000"},
+		{"-width", "wide"},
+		{"extra"},
+	}
+
+	for _, args := range tests {
+		code, _, _ := runCommand(append([]string{"render"}, args...)...)
+
+		assert.Equal(t, exitUsage, code, args)
+	}
+}
+
+func TestRenderMissingScene(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.yml")
+
+	code, _, stderr := runCommand("render", "-scene", missing, "-output", filepath.Join(t.TempDir(), "image.ppm"))
+
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "no such file")
+}
+
+func TestValidateDefaultScene(t *testing.T) {
+	code, _, stderr := runCommand("validate", "scenes/scene.yml")
+
+	assert.Equal(t, exitOK, code, stderr)
+}
@@ -0,0 +1,76 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	"math"
+	"math/bits"
+)
+
+// rectangular light sampled on a usteps x vsteps grid of cells
+type AreaLight struct {
+	corner      Tuple
+	uvec        Tuple
+	usteps      uint
+	vvec        Tuple
+	vsteps      uint
+	intensity   Color
+	Jitter      bool
+	Attenuation Attenuation
+}
+
+func NewAreaLight(corner Tuple, fullUvec Tuple, usteps uint, fullVvec Tuple, vsteps uint, intensity Color) *AreaLight {
+	return &AreaLight{corner,
+		fullUvec.Divide(float64(usteps)), usteps,
+		fullVvec.Divide(float64(vsteps)), vsteps,
+		intensity, true, Attenuation{}}
+}
+
+func (light AreaLight) GetIntensity() Color {
+	return light.intensity
+}
+
+func (light AreaLight) GetAmbient() Color {
+	return light.intensity
+}
+
+func (light AreaLight) GetAttenuation() Attenuation {
+	return light.Attenuation
+}
+
+func (light AreaLight) Samples(point Tuple) []LightSample {
+	samples := make([]LightSample, 0, light.usteps*light.vsteps)
+
+	for v := uint(0); v < light.vsteps; v++ {
+		for u := uint(0); u < light.usteps; u++ {
+			samples = append(samples, sampleFrom(light.PointOn(u, v, point), point, light.intensity))
+		}
+	}
+
+	return samples
+}
+
+// a point in cell (u, v), jittered deterministically by the shaded point
+func (light AreaLight) PointOn(u, v uint, point Tuple) Tuple {
+	ju, jv := 0.5, 0.5
+
+	if light.Jitter {
+		cell := uint64(v*light.usteps + u)
+		ju = jitter(point, cell*2)
+		jv = jitter(point, cell*2+1)
+	}
+
+	return light.corner.
+		Add(light.uvec.Multiply(float64(u) + ju)).
+		Add(light.vvec.Multiply(float64(v) + jv))
+}
+
+// hashes point and n to a value in [0, 1) so that renders are reproducible
+// no matter which worker shades the pixel
+func jitter(point Tuple, n uint64) float64 {
+	x := math.Float64bits(point.X) ^
+		bits.RotateLeft64(math.Float64bits(point.Y), 21) ^
+		bits.RotateLeft64(math.Float64bits(point.Z), 42) ^
+		(n+1)*0x9e3779b97f4a7c15
+
+	return unitFloat(mix64(x))
+}
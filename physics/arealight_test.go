@@ -0,0 +1,140 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAreaLight(t *testing.T) {
+	corner := NewPoint(0, 0, 0)
+	v1 := NewVector(2, 0, 0)
+	v2 := NewVector(0, 0, 1)
+
+	light := NewAreaLight(corner, v1, 4, v2, 2, White)
+
+	assert.Equal(t, corner, light.corner)
+	assert.Equal(t, NewVector(0.5, 0, 0), light.uvec)
+	assert.Equal(t, uint(4), light.usteps)
+	assert.Equal(t, NewVector(0, 0, 0.5), light.vvec)
+	assert.Equal(t, uint(2), light.vsteps)
+	assert.Equal(t, White, light.GetIntensity())
+	assert.Equal(t, 8, len(light.Samples(NewPoint(0, 0, 0))))
+}
+
+func TestPointOnAreaLight(t *testing.T) {
+	light := NewAreaLight(NewPoint(0, 0, 0), NewVector(2, 0, 0), 4, NewVector(0, 0, 1), 2, White)
+	light.Jitter = false
+
+	examples := []struct {
+		u        uint
+		v        uint
+		expected Tuple
+	}{
+		{0, 0, NewPoint(0.25, 0, 0.25)},
+		{1, 0, NewPoint(0.75, 0, 0.25)},
+		{0, 1, NewPoint(0.25, 0, 0.75)},
+		{2, 0, NewPoint(1.25, 0, 0.25)},
+		{3, 1, NewPoint(1.75, 0, 0.75)},
+	}
+
+	for _, example := range examples {
+		EqualTuple(t, example.expected, light.PointOn(example.u, example.v, NewPoint(0, 0, 0)))
+	}
+}
+
+func TestJitteredPointStaysInCell(t *testing.T) {
+	light := NewAreaLight(NewPoint(0, 0, 0), NewVector(2, 0, 0), 4, NewVector(0, 0, 1), 2, White)
+	point := NewPoint(1, -1, 2)
+
+	p := light.PointOn(2, 1, point)
+
+	assert.True(t, p.X >= 1 && p.X < 1.5)
+	assert.True(t, p.Z >= 0.5 && p.Z < 1)
+	assert.Equal(t, p, light.PointOn(2, 1, point))
+}
+
+func TestAreaLightIntensityAt(t *testing.T) {
+	world := DefaultWorld()
+	light := NewAreaLight(NewPoint(-0.5, -0.5, -5), NewVector(1, 0, 0), 2, NewVector(0, 1, 0), 2, White)
+	light.Jitter = false
+
+	examples := []struct {
+		point    Tuple
+		expected float64
+	}{
+		{NewPoint(0, 0, 2), 0.0},
+		{NewPoint(1, -1, 2), 0.25},
+		{NewPoint(1.5, 0, 2), 0.5},
+		{NewPoint(1.25, 1.25, 3), 0.75},
+		{NewPoint(0, 0, -2), 1.0},
+	}
+
+	for _, example := range examples {
+		assert.Equal(t, example.expected, world.LightVisibility(example.point, light))
+	}
+}
+
+func TestLightingSamplesAreaLight(t *testing.T) {
+	light := NewAreaLight(NewPoint(-0.5, -0.5, -5), NewVector(1, 0, 0), 2, NewVector(0, 1, 0), 2, White)
+	light.Jitter = false
+	shape := NewSphere()
+	shape.Material.Ambient = 0.1
+	shape.Material.Diffuse = 0.9
+	shape.Material.Specular = 0
+	eye := NewPoint(0, 0, -5)
+
+	examples := []struct {
+		point    Tuple
+		expected Color
+	}{
+		{NewPoint(0, 0, -1), NewColor(0.9965, 0.9965, 0.9965)},
+		{NewPoint(0, 0.7071, -0.7071), NewColor(0.62318, 0.62318, 0.62318)},
+	}
+
+	for _, example := range examples {
+		eyev := eye.Subtract(example.point).Normalize()
+		normalv := NewVector(example.point.X, example.point.Y, example.point.Z)
+
+		result := Lighting(shape.Material, shape, light, example.point, eyev, normalv, 1.0)
+
+		EqualColor(t, example.expected, result)
+	}
+}
+
+func TestShadeHitShadowsTheSamplesItShades(t *testing.T) {
+	world := DefaultWorld()
+	light := NewAreaLight(NewPoint(-1, 2, -6), NewVector(2, 0, 0), 4, NewVector(0, 2, 0), 4, White)
+	world.Lights = []Light{light}
+
+	blocker := NewCube()
+	blocker.Transform = blocker.Transform.Translate(0.5, 2, -3).Scale(0.5, 0.5, 0.1)
+	world.Objects = append(world.Objects, blocker)
+
+	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+	xs := world.Intersect(ray)
+	hit, _ := Hit(xs)
+	comps := PrepareComputations(hit, ray, xs)
+
+	// the samples jittered around the hit, tested from just above it
+	samples := light.Samples(comps.point)
+	visible := 0
+	for _, sample := range samples {
+		position := comps.point.Add(sample.Direction.Multiply(sample.Distance))
+		toLight := position.Subtract(comps.overPoint)
+		shadowXs := world.Intersect(NewRay(comps.overPoint, toLight.Normalize()))
+		if shadowHit, err := Hit(shadowXs); err != nil || shadowHit.T >= toLight.Magnitude() {
+			visible++
+		}
+	}
+
+	intensity := float64(visible) / float64(len(samples))
+	assert.Greater(t, intensity, 0.0)
+	assert.Less(t, intensity, 1.0)
+
+	material := world.Objects[0].GetMaterial()
+	expected := Lighting(material, world.Objects[0], light, comps.point, comps.eyev, comps.normalv, intensity)
+	assert.Equal(t, expected, world.ShadeHit(comps, 0))
+}
@@ -0,0 +1,51 @@
+package physics
+
+import "math"
+
+// falloff of a light with distance, 1 / (constant + linear*d + quadratic*d²);
+// the zero value means no attenuation
+type Attenuation struct {
+	Constant  float64
+	Linear    float64
+	Quadratic float64
+	// the light has no effect beyond Radius, 0 means no cutoff
+	Radius float64
+}
+
+// terms and radius cannot be negative
+func NewAttenuation(constant float64, linear float64, quadratic float64, radius float64) Attenuation {
+	if constant < 0 || linear < 0 || quadratic < 0 || radius < 0 {
+		panic("precondition - attenuation terms and radius cannot be negative")
+	}
+
+	return Attenuation{constant, linear, quadratic, radius}
+}
+
+func NewInverseSquareAttenuation() Attenuation {
+	return Attenuation{0, 0, 1, 0}
+}
+
+// lights at infinity such as DirectionalLight are not attenuated, their
+// distance says nothing about how bright they are
+func (attenuation Attenuation) Factor(distance float64) float64 {
+	if math.IsInf(distance, 1) {
+		return 1
+	}
+
+	if attenuation.Radius > 0 && distance > attenuation.Radius {
+		return 0
+	}
+
+	if attenuation.Constant == 0 && attenuation.Linear == 0 && attenuation.Quadratic == 0 {
+		return 1
+	}
+
+	denominator := attenuation.Constant + attenuation.Linear*distance + attenuation.Quadratic*distance*distance
+
+	// at the light itself with no constant term, the falloff has no value
+	if denominator <= 0 {
+		return 1
+	}
+
+	return 1 / denominator
+}
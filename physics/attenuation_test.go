@@ -0,0 +1,113 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoAttenuation(t *testing.T) {
+	attenuation := Attenuation{}
+
+	assert.Equal(t, 1.0, attenuation.Factor(0))
+	assert.Equal(t, 1.0, attenuation.Factor(100))
+	assert.Equal(t, 1.0, attenuation.Factor(math.Inf(1)))
+}
+
+func TestInverseSquareAttenuation(t *testing.T) {
+	attenuation := NewInverseSquareAttenuation()
+
+	assert.Equal(t, 0.25, attenuation.Factor(2))
+	assert.Equal(t, 0.01, attenuation.Factor(10))
+	assert.Equal(t, 1.0, attenuation.Factor(math.Inf(1)))
+}
+
+func TestConstantLinearQuadraticAttenuation(t *testing.T) {
+	attenuation := Attenuation{1, 0.5, 0.25, 0}
+
+	assert.Equal(t, 1.0, attenuation.Factor(0))
+	assert.Equal(t, 1/3.0, attenuation.Factor(2))
+}
+
+func TestLinearAttenuationNearLight(t *testing.T) {
+	attenuation := Attenuation{Linear: 1}
+
+	assert.Equal(t, 1000.0, attenuation.Factor(0.001))
+	assert.Equal(t, 1.0, attenuation.Factor(0))
+}
+
+func TestNewAttenuation(t *testing.T) {
+	assert.Equal(t, Attenuation{1, 0.5, 0.25, 10}, NewAttenuation(1, 0.5, 0.25, 10))
+
+	assert.PanicsWithValue(t, "precondition - attenuation terms and radius cannot be negative", func() {
+		NewAttenuation(1, -0.5, 0, 0)
+	})
+}
+
+func TestAttenuationRadiusCutoff(t *testing.T) {
+	attenuation := Attenuation{Radius: 5}
+
+	assert.Equal(t, 1.0, attenuation.Factor(5))
+	assert.Equal(t, 0.0, attenuation.Factor(5.1))
+}
+
+func TestLightingWithAttenuatedLight(t *testing.T) {
+	material := NewMaterial()
+	material.Ambient = 0
+	material.Specular = 0
+	position := NewPoint(0, 0, 0)
+	eyev := NewVector(0, 0, -1)
+	normalv := NewVector(0, 0, -1)
+
+	examples := []Light{
+		NewPointLight(NewPoint(0, 0, -2), White),
+		NewSpotLight(NewPoint(0, 0, -2), NewVector(0, 0, 1), math.Pi/4, math.Pi/2, White),
+		NewAreaLight(NewPoint(-0.001, -0.001, -2), NewVector(0.002, 0, 0), 1, NewVector(0, 0.002, 0), 1, White),
+	}
+
+	for _, light := range examples {
+		switch light := light.(type) {
+		case *PointLight:
+			light.Attenuation = NewInverseSquareAttenuation()
+		case *SpotLight:
+			light.Attenuation = NewInverseSquareAttenuation()
+		case *AreaLight:
+			light.Attenuation = NewInverseSquareAttenuation()
+		}
+
+		result := Lighting(material, NewSphere(), light, position, eyev, normalv, 1.0)
+
+		EqualColor(t, NewColor(0.225, 0.225, 0.225), result)
+	}
+}
+
+func TestLightingBeyondLightRadius(t *testing.T) {
+	material := NewMaterial()
+	eyev := NewVector(0, 0, -1)
+	normalv := NewVector(0, 0, -1)
+	light := NewPointLight(NewPoint(0, 0, -200), White)
+	light.Attenuation = Attenuation{Radius: 100}
+
+	result := Lighting(material, NewSphere(), light, NewPoint(0, 0, 0), eyev, normalv, 1.0)
+
+	EqualColor(t, NewColor(0.1, 0.1, 0.1), result)
+}
+
+func TestDirectionalLightIgnoresAttenuation(t *testing.T) {
+	material := NewMaterial()
+	eyev := NewVector(0, 0, -1)
+	normalv := NewVector(0, 0, -1)
+	light := NewDirectionalLight(NewVector(0, 0, 1), White)
+	expected := Lighting(material, NewSphere(), light, NewPoint(0, 0, 0), eyev, normalv, 1.0)
+
+	for _, attenuation := range []Attenuation{NewInverseSquareAttenuation(), {1, 0.5, 0.25, 0}, {Radius: 100}} {
+		light.Attenuation = attenuation
+
+		result := Lighting(material, NewSphere(), light, NewPoint(0, 0, 0), eyev, normalv, 1.0)
+
+		EqualColor(t, expected, result)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	. "go-raytracer/core"
 	. "go-raytracer/image"
 	"math"
+	"runtime"
+	"sync"
 )
 
 type Camera struct {
@@ -11,13 +13,56 @@ type Camera struct {
 	vsize         uint
 	fieldOfView   float64
 	Transform     Matrix
-	cachedInverse Matrix
+	cachedInverse CachedInverse
 	pixelSize     float64
 	halfWidth     float64
 	halfHeight    float64
+	Workers       int
+	// how many times rays may bounce for reflection and refraction
+	MaxDepth uint
+	// rays traced per pixel, spread over a grid within the pixel
+	Samples    uint
+	SampleMode SampleMode
+	// varies the offsets of jittered samples, renders with the same seed
+	// are identical
+	Seed uint64
+	// when above 0, only pixels that differ from a neighbor by more than
+	// this in any channel get more than one sample
+	AdaptiveThreshold float64
+	// radius of the lens, 0 is a pinhole camera with everything in focus
+	Aperture float64
+	// distance from the camera to the plane that is in focus
+	FocalDistance float64
+	// number of straight edges of the aperture, below 3 the aperture is a
+	// disk
+	ApertureBlades uint
+	// rotation of a polygonal aperture in radians
+	ApertureRotation float64
+	// how points on the image map to rays, perspective when nil
+	Projection Projection
+	// how colors along rays are computed, WhittedIntegrator when nil
+	Integrator Integrator
 }
 
 func NewCamera(hsize uint, vsize uint, fieldOfView float64) Camera {
+	camera := Camera{
+		Transform:     NewIdentityMatrix(),
+		Workers:       runtime.NumCPU(),
+		MaxDepth:      4,
+		Samples:       1,
+		SampleMode:    SampleStratified,
+		FocalDistance: 1,
+		Projection:    PerspectiveProjection{},
+		Integrator:    WhittedIntegrator{},
+	}
+	camera.SetView(hsize, vsize, fieldOfView)
+
+	return camera
+}
+
+// changes the size of the image and the field of view, keeping the other
+// settings of the camera
+func (camera *Camera) SetView(hsize uint, vsize uint, fieldOfView float64) {
 	var halfWidth float64
 	var halfHeight float64
 
@@ -32,40 +77,203 @@ func NewCamera(hsize uint, vsize uint, fieldOfView float64) Camera {
 		halfHeight = halfView
 	}
 
-	pixelSize := halfWidth * 2 / float64(hsize)
+	camera.hsize = hsize
+	camera.vsize = vsize
+	camera.fieldOfView = fieldOfView
+	camera.pixelSize = halfWidth * 2 / float64(hsize)
+	camera.halfWidth = halfWidth
+	camera.halfHeight = halfHeight
+}
+
+func (camera *Camera) HSize() uint {
+	return camera.hsize
+}
+
+func (camera *Camera) VSize() uint {
+	return camera.vsize
+}
 
-	return Camera{hsize, vsize, fieldOfView, NewIdentityMatrix(),
-		nil, pixelSize, halfWidth, halfHeight}
+func (camera *Camera) FieldOfView() float64 {
+	return camera.fieldOfView
 }
 
 func (camera *Camera) RayForPixel(px uint, py uint) Ray {
-	if camera.cachedInverse == nil {
-		camera.cachedInverse = camera.Transform.Inverse()
+	return camera.RayForPixelOffset(px, py, 0.5, 0.5)
+}
+
+// ray through a point within the pixel, offsets range from 0 to 1. The
+// ray is empty where the projection does not cover the point
+func (camera *Camera) RayForPixelOffset(px uint, py uint, dx float64, dy float64) Ray {
+	ray, _ := camera.imageRay(float64(px)+dx, float64(py)+dy, 0, 0)
+
+	return ray
+}
+
+// ray through a point within the pixel that starts from a point on the
+// lens, lu and lv range from 0 to 1 and pick the point on the aperture
+func (camera *Camera) RayThroughLens(px uint, py uint, dx float64, dy float64, lu float64, lv float64) Ray {
+	if camera.Aperture <= 0 {
+		return camera.RayForPixelOffset(px, py, dx, dy)
+	}
+
+	lensX, lensY := camera.AperturePoint(lu, lv)
+	ray, _ := camera.imageRay(float64(px)+dx, float64(py)+dy, lensX, lensY)
+
+	return ray
+}
+
+// world space ray from camera.Projection, false where it does not cover
+// image point (x, y)
+func (camera *Camera) imageRay(x float64, y float64, lensX float64, lensY float64) (Ray, bool) {
+	projection := camera.Projection
+	if projection == nil {
+		projection = PerspectiveProjection{}
 	}
 
-	xoffset := (float64(px) + 0.5) * camera.pixelSize
-	yoffset := (float64(py) + 0.5) * camera.pixelSize
+	start, through, covered := projection.CameraRay(camera, x, y, lensX, lensY)
+	if !covered {
+		return Ray{}, false
+	}
 
-	worldX := camera.halfWidth - xoffset
-	worldY := camera.halfHeight - yoffset
+	inverse := camera.cachedInverse.Get(camera.Transform)
 
-	pixel := camera.cachedInverse.MultiplyTuple(NewPoint(worldX, worldY, -1))
-	origin := camera.cachedInverse.MultiplyTuple(NewPoint(0, 0, 0))
-	direction := pixel.Subtract(origin).Normalize()
+	point := inverse.MultiplyTuple(through)
+	origin := inverse.MultiplyTuple(start)
+	direction := point.Subtract(origin).Normalize()
 
-	return NewRay(origin, direction)
+	return NewRay(origin, direction), true
 }
 
+// renders rows in parallel on camera.Workers goroutines
 func (camera *Camera) Render(world World) Canvas {
+	world.refreshBVH()
+
+	if camera.Samples > 1 && camera.AdaptiveThreshold > 0 {
+		return camera.renderAdaptive(world)
+	}
+
+	return camera.renderPass(func(x uint, y uint) Color {
+		return camera.pixelColor(world, x, y)
+	})
+}
+
+// traces one ray per pixel, then all samples only for pixels that differ
+// from a neighbor by more than camera.AdaptiveThreshold
+func (camera *Camera) renderAdaptive(world World) Canvas {
+	preview := camera.renderPass(func(x uint, y uint) Color {
+		return camera.lensColor(world, x, y, 0.5, 0.5, 0)
+	})
+
+	return camera.renderPass(func(x uint, y uint) Color {
+		if camera.isEdge(preview, x, y) {
+			return camera.pixelColor(world, x, y)
+		}
+
+		return preview.Pixel[x][y]
+	})
+}
+
+func (camera *Camera) isEdge(preview Canvas, x uint, y uint) bool {
+	color := preview.Pixel[x][y]
+	neighbors := [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+
+	for _, offset := range neighbors {
+		nx, ny := int(x)+offset[0], int(y)+offset[1]
+		if nx < 0 || ny < 0 || nx >= int(camera.hsize) || ny >= int(camera.vsize) {
+			continue
+		}
+
+		if colorDifference(color, preview.Pixel[nx][ny]) > camera.AdaptiveThreshold {
+			return true
+		}
+	}
+
+	return false
+}
+
+// largest difference between the channels of two colors
+func colorDifference(a Color, b Color) float64 {
+	return math.Max(math.Abs(a.Red-b.Red), math.Max(math.Abs(a.Green-b.Green), math.Abs(a.Blue-b.Blue)))
+}
+
+func (camera *Camera) renderPass(shade func(x uint, y uint) Color) Canvas {
 	image := NewCanvas(camera.hsize, camera.vsize)
 
+	workers := camera.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	rows := make(chan uint, camera.vsize)
 	for y := uint(0); y < camera.vsize; y++ {
-		for x := uint(0); x < camera.hsize; x++ {
-			ray := camera.RayForPixel(x, y)
-			color := world.ColorAt(ray, 4)
-			image.WritePixel(x, y, color)
-		}
+		rows <- y
 	}
+	close(rows)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for y := range rows {
+				for x := uint(0); x < camera.hsize; x++ {
+					image.WritePixel(x, y, shade(x, y))
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
 
 	return image
 }
+
+// color of sample i of pixel (x, y), through the pixel center when there
+// is only one sample
+func (camera *Camera) sampleColor(world World, x uint, y uint, i uint) Color {
+	dx, dy := 0.5, 0.5
+	if camera.Samples > 1 {
+		dx, dy = camera.SampleOffset(x, y, i)
+	}
+
+	return camera.lensColor(world, x, y, dx, dy, i)
+}
+
+// color seen through an offset within pixel (x, y) from the lens position
+// of sample i, black where the projection does not cover the point
+func (camera *Camera) lensColor(world World, x uint, y uint, dx float64, dy float64, i uint) Color {
+	lensX, lensY := 0.0, 0.0
+	if camera.Aperture > 0 {
+		lensX, lensY = camera.AperturePoint(camera.LensOffset(x, y, i))
+	}
+
+	ray, covered := camera.imageRay(float64(x)+dx, float64(y)+dy, lensX, lensY)
+	if !covered {
+		return Black
+	}
+
+	integrator := camera.Integrator
+	if integrator == nil {
+		integrator = WhittedIntegrator{}
+	}
+
+	// a different stream from the pixel and lens offsets
+	const salt = 0xbb67ae8584caa73b
+
+	return integrator.Trace(world, ray, camera.MaxDepth, NewSampler(camera.sampleHash(x, y, i, salt)))
+}
+
+func (camera *Camera) pixelColor(world World, x uint, y uint) Color {
+	if camera.Samples <= 1 {
+		return camera.sampleColor(world, x, y, 0)
+	}
+
+	color := Black
+	for i := uint(0); i < camera.Samples; i++ {
+		color = color.Add(camera.sampleColor(world, x, y, i))
+	}
+
+	return color.MultiplyScalar(1 / float64(camera.Samples))
+}
@@ -2,6 +2,7 @@ package physics
 
 import (
 	. "go-raytracer/core"
+	. "go-raytracer/geometry"
 	"math"
 	"testing"
 
@@ -35,6 +36,8 @@ func TestNewCamera(t *testing.T) {
 	assert.Equal(t, uint(120), camera.vsize)
 	assert.Equal(t, math.Pi/2, camera.fieldOfView)
 	assert.Equal(t, NewIdentityMatrix(), camera.Transform)
+	assert.Equal(t, uint(4), camera.MaxDepth)
+	assert.Equal(t, uint(1), camera.Samples)
 }
 
 func TestPixelSizeHorizontalCanvas(t *testing.T) {
@@ -81,6 +84,15 @@ func TestConstructRayCornerOfCanvas(t *testing.T) {
 	EqualTuple(t, NewVector(0.66519, 0.33259, -0.66851), ray.Direction)
 }
 
+func TestConstructRayThroughPixelOffset(t *testing.T) {
+	camera := NewCamera(201, 101, math.Pi/2)
+
+	ray := camera.RayForPixelOffset(100, 50, 0, 0)
+
+	assert.Equal(t, NewPoint(0, 0, 0), ray.Origin)
+	EqualTuple(t, NewVector(1.0/201, 1.0/201, -1).Normalize(), ray.Direction)
+}
+
 func TestConstructRayCameraTransformed(t *testing.T) {
 	var hsize uint = 201
 	var vsize uint = 101
@@ -107,3 +119,52 @@ func TestRenderWorldWithCamera(t *testing.T) {
 
 	EqualColor(t, NewColor(0.38066, 0.47583, 0.2855), result)
 }
+
+func TestParallelRenderMatchesSerialRender(t *testing.T) {
+	world := DefaultWorld()
+	camera := NewCamera(40, 30, math.Pi/2)
+	from := NewPoint(0, 0, -5)
+	to := NewPoint(0, 0, 0)
+	up := NewVector(0, 1, 0)
+	camera.Transform = ViewTransform(from, to, up)
+
+	camera.Workers = 1
+	serial := camera.Render(world)
+	camera.Workers = 8
+	parallel := camera.Render(world)
+
+	assert.Equal(t, serial.ToPPM(), parallel.ToPPM())
+}
+
+func TestRenderAveragesSamplesWithinPixel(t *testing.T) {
+	world := DefaultWorld()
+	camera := NewCamera(11, 11, math.Pi/2)
+	camera.Transform = ViewTransform(NewPoint(0, 0, -5), NewPoint(0, 0, 0), NewVector(0, 1, 0))
+	camera.Samples = 4
+
+	expected := Black
+	for _, offset := range [][2]float64{{0.25, 0.25}, {0.75, 0.25}, {0.25, 0.75}, {0.75, 0.75}} {
+		ray := camera.RayForPixelOffset(3, 4, offset[0], offset[1])
+		expected = expected.Add(world.ColorAt(ray, 4))
+	}
+
+	image := camera.Render(world)
+
+	EqualColor(t, expected.MultiplyScalar(0.25), image.Pixel[3][4])
+}
+
+func TestRenderUsesMaxDepth(t *testing.T) {
+	world := DefaultWorld()
+	floor := NewPlane()
+	floor.Transform = floor.Transform.Translate(0, -1, 0)
+	floor.Material.Reflective = 0.5
+	world.Objects = append(world.Objects, floor)
+	camera := NewCamera(11, 11, math.Pi/2)
+	camera.Transform = ViewTransform(NewPoint(0, 0, -5), NewPoint(0, 0, 0), NewVector(0, 1, 0))
+	camera.MaxDepth = 0
+
+	image := camera.Render(world)
+
+	assert.Equal(t, world.ColorAt(camera.RayForPixel(5, 8), 0), image.Pixel[5][8])
+	assert.NotEqual(t, world.ColorAt(camera.RayForPixel(5, 8), 4), image.Pixel[5][8])
+}
@@ -19,6 +19,10 @@ type Comps struct {
 	underPoint Tuple
 	n1         float64
 	n2         float64
+	// object the ray passed through to reach the hit, nil when it came
+	// through empty space, and the distance it travelled inside
+	medium         Shape
+	mediumDistance float64
 }
 
 func PrepareComputations(intersection Intersection, ray Ray, xs []Intersection) Comps {
@@ -29,7 +33,7 @@ func PrepareComputations(intersection Intersection, ray Ray, xs []Intersection)
 
 	comps.point = ray.Position(comps.t)
 	comps.eyev = ray.Direction.Multiply(-1)
-	comps.normalv = comps.object.NormalAt(comps.point)
+	comps.normalv = comps.object.NormalAt(comps.point, intersection)
 	comps.reflectv = ray.Direction.Reflect(comps.normalv)
 
 	if comps.normalv.Dot(comps.eyev) < 0 {
@@ -41,6 +45,8 @@ func PrepareComputations(intersection Intersection, ray Ray, xs []Intersection)
 	comps.underPoint = comps.point.Subtract(comps.normalv.Multiply(Epsilon))
 
 	var containers []Shape
+	// where the ray entered each container
+	var entries []float64
 
 	for _, x := range xs {
 		if x == intersection {
@@ -49,12 +55,18 @@ func PrepareComputations(intersection Intersection, ray Ray, xs []Intersection)
 			} else {
 				last := len(containers) - 1
 				comps.n1 = containers[last].GetMaterial().RefractiveIndex
+				comps.medium = containers[last]
+				// rays starting inside entered behind their origin
+				comps.mediumDistance = comps.t - math.Max(entries[last], 0)
 			}
 		}
 
+		// surfaces of the same CSG shape bound a single medium
+		solid := SolidOf(x.Object)
+
 		find := -1
 		for index, object := range containers {
-			if object == x.Object {
+			if SolidOf(object) == solid {
 				find = index
 				break
 			}
@@ -62,9 +74,11 @@ func PrepareComputations(intersection Intersection, ray Ray, xs []Intersection)
 
 		if find == -1 {
 			containers = append(containers, x.Object)
+			entries = append(entries, x.T)
 		} else {
 			// remove found element
 			containers = append(containers[:find], containers[find+1:]...)
+			entries = append(entries[:find], entries[find+1:]...)
 		}
 
 		if x == intersection {
@@ -82,6 +96,38 @@ func PrepareComputations(intersection Intersection, ray Ray, xs []Intersection)
 	return comps
 }
 
+// fraction of the light leaving the hit that is left after the medium
+// absorbs some on the way to the ray's origin
+func (comps Comps) Transmittance() Color {
+	if comps.medium == nil {
+		return White
+	}
+
+	return comps.medium.GetMaterial().Transmittance(comps.mediumDistance)
+}
+
+// direction of the ray refracted through the surface, false on total
+// internal reflection
+func (comps Comps) RefractedDirection() (Tuple, bool) {
+	return refract(comps.eyev, comps.normalv, comps.n1/comps.n2)
+}
+
+// direction of a ray towards eyev refracted through a surface facing
+// normal, nRatio is the refractive index on the eye's side over the index
+// on the other side. false on total internal reflection
+func refract(eyev Tuple, normal Tuple, nRatio float64) (Tuple, bool) {
+	cosI := eyev.Dot(normal)
+	sin2T := math.Pow(nRatio, 2) * (1 - math.Pow(cosI, 2))
+
+	if sin2T > 1 {
+		return Tuple{}, false
+	}
+
+	cosT := math.Sqrt(1.0 - sin2T)
+
+	return normal.Multiply(nRatio*cosI - cosT).Subtract(eyev.Multiply(nRatio)), true
+}
+
 func (comps Comps) Schlick() float64 {
 	cos := comps.eyev.Dot(comps.normalv)
 
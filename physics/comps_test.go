@@ -112,6 +112,55 @@ func TestN1andN2VariousIntersections(t *testing.T) {
 	}
 }
 
+func TestMediumDistanceVariousIntersections(t *testing.T) {
+	a := NewGlassSphere()
+	a.Material.RefractiveIndex = 1.5
+	b := NewGlassSphere()
+	b.Material.RefractiveIndex = 2
+	c := NewGlassSphere()
+	c.Material.RefractiveIndex = 2.5
+
+	ray := NewRay(NewPoint(0, 0, -4), NewVector(0, 0, 1))
+	xs := []Intersection{
+		NewIntersection(2, a),
+		NewIntersection(2.75, b),
+		NewIntersection(3.25, c),
+		NewIntersection(4.75, b),
+		NewIntersection(5.25, c),
+		NewIntersection(6, a)}
+
+	examples := []struct {
+		index    int
+		medium   Shape
+		distance float64
+	}{
+		{0, nil, 0},
+		{1, a, 0.75},
+		{2, b, 0.5},
+		{3, c, 1.5},
+		{4, c, 2},
+		{5, a, 4}}
+
+	for _, example := range examples {
+		comps := PrepareComputations(xs[example.index], ray, xs)
+
+		assert.Equal(t, example.medium, comps.medium)
+		assert.Equal(t, example.distance, comps.mediumDistance)
+	}
+}
+
+func TestMediumDistanceFromOriginInside(t *testing.T) {
+	shape := NewGlassSphere()
+	shape.Material.Absorption = NewColor(1, 0, 0)
+
+	ray := NewRay(NewPoint(0, 0, 0), NewVector(0, 0, 1))
+	xs := []Intersection{NewIntersection(-1, shape), NewIntersection(1, shape)}
+	comps := PrepareComputations(xs[1], ray, xs)
+
+	assert.Equal(t, 1.0, comps.mediumDistance)
+	assert.Equal(t, NewColor(math.Exp(-1), 1, 1), comps.Transmittance())
+}
+
 func TestUnderPointOffsetBelowSurface(t *testing.T) {
 	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
 	shape := NewGlassSphere()
@@ -161,3 +210,57 @@ func TestSchlickApproxWithSmallAngle(t *testing.T) {
 
 	assert.True(t, FloatEquals(reflectance, 0.48873))
 }
+
+func TestN1andN2ThroughClosedCylinder(t *testing.T) {
+	cylinder := NewCylinder()
+	cylinder.Minimum = -1
+	cylinder.Maximum = 1
+	cylinder.Closed = true
+	cylinder.Material.Transparency = 1
+	cylinder.Material.RefractiveIndex = 1.5
+
+	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+	xs := cylinder.Intersects(ray)
+
+	entry := PrepareComputations(xs[0], ray, xs)
+	exit := PrepareComputations(xs[1], ray, xs)
+
+	assert.Equal(t, 1.0, entry.n1)
+	assert.Equal(t, 1.5, entry.n2)
+	assert.Equal(t, 1.5, exit.n1)
+	assert.Equal(t, 1.0, exit.n2)
+}
+
+func TestPrepareComputationsOnSmoothTriangle(t *testing.T) {
+	triangle := NewSmoothTriangle(
+		NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0),
+		NewVector(0, 1, 0), NewVector(-1, 0, 0), NewVector(1, 0, 0))
+	intersection := NewIntersectionWithUV(1, triangle, 0.45, 0.25)
+	ray := NewRay(NewPoint(-0.2, 0.3, -2), NewVector(0, 0, 1))
+
+	comps := PrepareComputations(intersection, ray, []Intersection{intersection})
+
+	EqualTuple(t, NewVector(-0.5547, 0.83205, 0), comps.normalv)
+}
+
+func TestPrepareComputationsOnCSGLens(t *testing.T) {
+	left := NewGlassSphere()
+	left.Transform = left.Transform.Translate(0, 0, 0.5)
+	right := NewGlassSphere()
+	right.Transform = right.Transform.Translate(0, 0, -0.5)
+	lens := NewCSG(CSGIntersection, left, right)
+	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+
+	xs := lens.Intersects(ray)
+	entry := PrepareComputations(xs[0], ray, xs)
+	exit := PrepareComputations(xs[1], ray, xs)
+
+	assert.Equal(t, 2, len(xs))
+	assert.Equal(t, left, entry.object)
+	assert.Equal(t, right, exit.object)
+	EqualTuple(t, NewVector(0, 0, -1), entry.normalv)
+	assert.Equal(t, 1.0, entry.n1)
+	assert.Equal(t, 1.5, entry.n2)
+	assert.Equal(t, 1.5, exit.n1)
+	assert.Equal(t, 1.0, exit.n2)
+}
@@ -0,0 +1,33 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	"math"
+)
+
+// light infinitely far away shining along direction, such as the sun
+type DirectionalLight struct {
+	direction   Tuple
+	intensity   Color
+	Attenuation Attenuation
+}
+
+func NewDirectionalLight(direction Tuple, intensity Color) *DirectionalLight {
+	return &DirectionalLight{direction.Normalize(), intensity, Attenuation{}}
+}
+
+func (light DirectionalLight) GetIntensity() Color {
+	return light.intensity
+}
+
+func (light DirectionalLight) GetAmbient() Color {
+	return light.intensity
+}
+
+func (light DirectionalLight) GetAttenuation() Attenuation {
+	return light.Attenuation
+}
+
+func (light DirectionalLight) Samples(point Tuple) []LightSample {
+	return []LightSample{{light.direction.Multiply(-1), math.Inf(1), light.intensity}}
+}
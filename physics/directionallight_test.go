@@ -0,0 +1,50 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDirectionalLight(t *testing.T) {
+	light := NewDirectionalLight(NewVector(0, -2, 0), White)
+
+	assert.Equal(t, NewVector(0, -1, 0), light.direction)
+	assert.Equal(t, White, light.GetIntensity())
+}
+
+func TestDirectionalLightSample(t *testing.T) {
+	light := NewDirectionalLight(NewVector(0, -1, 0), White)
+
+	samples := light.Samples(NewPoint(3, 4, 5))
+
+	assert.Equal(t, 1, len(samples))
+	EqualTuple(t, NewVector(0, 1, 0), samples[0].Direction)
+	assert.True(t, math.IsInf(samples[0].Distance, 1))
+}
+
+func TestLightingWithDirectionalLight(t *testing.T) {
+	material := NewMaterial()
+	eyev := NewVector(0, 0, -1)
+	normalv := NewVector(0, 0, -1)
+	light := NewDirectionalLight(NewVector(0, 0, 1), White)
+
+	// the position of the point does not matter
+	c1 := Lighting(material, NewSphere(), light, NewPoint(0, 0, 0), eyev, normalv, 1.0)
+	c2 := Lighting(material, NewSphere(), light, NewPoint(100, -50, 0), eyev, normalv, 1.0)
+
+	EqualColor(t, NewColor(1.9, 1.9, 1.9), c1)
+	EqualColor(t, c1, c2)
+}
+
+func TestDirectionalLightShadowHasNoMaximumDistance(t *testing.T) {
+	world := DefaultWorld()
+	light := NewDirectionalLight(NewVector(0, -1, 0), White)
+
+	// the sphere is 99 units away but still blocks the sun
+	assert.Equal(t, 0.0, world.LightVisibility(NewPoint(0, -100, 0), light))
+	assert.Equal(t, 1.0, world.LightVisibility(NewPoint(0, 100, 0), light))
+}
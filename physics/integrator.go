@@ -0,0 +1,20 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+)
+
+// computes the color seen along camera rays
+type Integrator interface {
+	// color seen along ray with at most depth bounces, sampler gives the
+	// random numbers of one camera sample
+	Trace(world World, ray Ray, depth uint, sampler *Sampler) Color
+}
+
+// recursive ray tracing with World.ShadeHit, perfect reflections and
+// refractions with the ambient term standing in for indirect light
+type WhittedIntegrator struct{}
+
+func (integrator WhittedIntegrator) Trace(world World, ray Ray, depth uint, sampler *Sampler) Color {
+	return world.ColorAt(ray, depth)
+}
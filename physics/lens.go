@@ -0,0 +1,68 @@
+package physics
+
+import (
+	"math"
+)
+
+// picks the point on the lens for sample i of pixel (x, y), from 0 to 1 in
+// both directions and deterministic for a given camera.Seed
+func (camera *Camera) LensOffset(x uint, y uint, i uint) (float64, float64) {
+	// a different stream from the jittered pixel offsets of SampleOffset
+	const salt = 0x6a09e667f3bcc909
+
+	hash := camera.sampleHash(x, y, i, salt)
+
+	return unitFloat(hash), unitFloat(mix64(hash))
+}
+
+// point on the aperture in camera space for lu and lv from 0 to 1, spread
+// evenly over a disk or a polygon with camera.Aperture as its radius
+func (camera *Camera) AperturePoint(lu float64, lv float64) (float64, float64) {
+	var x, y float64
+
+	if camera.ApertureBlades < 3 {
+		x, y = concentricDisk(lu, lv)
+	} else {
+		x, y = regularPolygon(camera.ApertureBlades, camera.ApertureRotation, lu, lv)
+	}
+
+	return x * camera.Aperture, y * camera.Aperture
+}
+
+// maps the unit square onto the unit disk keeping areas proportional, so
+// evenly spread inputs stay evenly spread
+func concentricDisk(u float64, v float64) (float64, float64) {
+	a, b := 2*u-1, 2*v-1
+	if a == 0 && b == 0 {
+		return 0, 0
+	}
+
+	var radius, theta float64
+	if math.Abs(a) > math.Abs(b) {
+		radius = a
+		theta = math.Pi / 4 * (b / a)
+	} else {
+		radius = b
+		theta = math.Pi/2 - math.Pi/4*(a/b)
+	}
+
+	return radius * math.Cos(theta), radius * math.Sin(theta)
+}
+
+// maps the unit square onto a regular polygon inscribed in the unit
+// circle, u picks one of the triangles between the center and an edge and
+// the rest of u and v pick a point within it
+func regularPolygon(sides uint, rotation float64, u float64, v float64) (float64, float64) {
+	scaled := u * float64(sides)
+	side := math.Min(math.Floor(scaled), float64(sides-1))
+	fraction := scaled - side
+
+	angle := 2 * math.Pi / float64(sides)
+	x1, y1 := math.Cos(rotation+side*angle), math.Sin(rotation+side*angle)
+	x2, y2 := math.Cos(rotation+(side+1)*angle), math.Sin(rotation+(side+1)*angle)
+
+	// uniform point in the triangle with corners at the center, 1 and 2
+	distance := math.Sqrt(fraction)
+
+	return distance * ((1-v)*x1 + v*x2), distance * ((1-v)*y1 + v*y2)
+}
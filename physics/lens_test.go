@@ -0,0 +1,158 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// point where a ray crosses the plane z = -distance in camera space, for a
+// camera without a transform
+func focalPlanePoint(ray Ray, distance float64) Tuple {
+	t := (-distance - ray.Origin.Z) / ray.Direction.Z
+
+	return ray.Origin.Add(ray.Direction.Multiply(t))
+}
+
+func TestLensRayWithoutAperture(t *testing.T) {
+	camera := NewCamera(201, 101, math.Pi/2)
+
+	assert.Equal(t, camera.RayForPixelOffset(10, 20, 0.3, 0.6), camera.RayThroughLens(10, 20, 0.3, 0.6, 0.9, 0.1))
+}
+
+func TestLensCenterMatchesPinhole(t *testing.T) {
+	camera := NewCamera(201, 101, math.Pi/2)
+	camera.Transform = camera.Transform.RotateY(math.Pi/4).Translate(0, -2, 5)
+	camera.Aperture = 0.5
+	camera.FocalDistance = 3
+
+	pinhole := camera.RayForPixel(40, 30)
+	lens := camera.RayThroughLens(40, 30, 0.5, 0.5, 0.5, 0.5)
+
+	EqualTuple(t, pinhole.Origin, lens.Origin)
+	EqualTuple(t, pinhole.Direction, lens.Direction)
+}
+
+func TestLensRaysMeetOnFocalPlane(t *testing.T) {
+	camera := NewCamera(201, 101, math.Pi/2)
+	camera.Aperture = 0.25
+	camera.FocalDistance = 4
+
+	expected := focalPlanePoint(camera.RayForPixel(70, 20), 4)
+
+	for _, offset := range [][2]float64{{0, 0}, {1, 0.5}, {0.2, 0.9}, {0.75, 0.3}} {
+		ray := camera.RayThroughLens(70, 20, 0.5, 0.5, offset[0], offset[1])
+
+		assert.NotEqual(t, NewPoint(0, 0, 0), ray.Origin)
+		EqualTuple(t, expected, focalPlanePoint(ray, 4))
+	}
+}
+
+func TestConcentricDisk(t *testing.T) {
+	x, y := concentricDisk(1, 0.5)
+	assert.InDelta(t, 1, x, Epsilon)
+	assert.InDelta(t, 0, y, Epsilon)
+
+	x, y = concentricDisk(0.5, 1)
+	assert.InDelta(t, 0, x, Epsilon)
+	assert.InDelta(t, 1, y, Epsilon)
+
+	x, y = concentricDisk(0.5, 0.5)
+	assert.Equal(t, []float64{0, 0}, []float64{x, y})
+
+	for u := 0.0; u <= 1; u += 0.125 {
+		for v := 0.0; v <= 1; v += 0.125 {
+			x, y := concentricDisk(u, v)
+			assert.LessOrEqual(t, math.Hypot(x, y), 1+Epsilon)
+		}
+	}
+}
+
+func TestAperturePointOnDisk(t *testing.T) {
+	camera := NewCamera(10, 10, math.Pi/2)
+	camera.Aperture = 0.5
+
+	x, y := camera.AperturePoint(1, 0.5)
+
+	assert.InDelta(t, 0.5, x, Epsilon)
+	assert.InDelta(t, 0, y, Epsilon)
+}
+
+func TestAperturePointOnPolygon(t *testing.T) {
+	camera := NewCamera(10, 10, math.Pi/2)
+	camera.Aperture = 2
+	camera.ApertureBlades = 6
+	camera.ApertureRotation = math.Pi / 6
+
+	// the first corner of the hexagon
+	x, y := camera.AperturePoint(1.0/6-1e-12, 0)
+	assert.InDelta(t, 2*math.Cos(math.Pi/6), x, 1e-5)
+	assert.InDelta(t, 2*math.Sin(math.Pi/6), y, 1e-5)
+
+	// the middle of the last edge
+	x, y = camera.AperturePoint(1, 0.5)
+	apothem := 2 * math.Cos(math.Pi/6)
+	assert.InDelta(t, apothem, math.Hypot(x, y), Epsilon)
+
+	// everything stays inside the inscribed circle
+	for u := 0.0; u <= 1; u += 0.05 {
+		for v := 0.0; v <= 1; v += 0.1 {
+			x, y := camera.AperturePoint(u, v)
+			assert.LessOrEqual(t, math.Hypot(x, y), 2+Epsilon)
+		}
+	}
+}
+
+func TestLensOffsetIsDeterministic(t *testing.T) {
+	camera := NewCamera(10, 10, math.Pi/2)
+
+	u1, v1 := camera.LensOffset(3, 4, 2)
+	u2, v2 := camera.LensOffset(3, 4, 2)
+	u3, _ := camera.LensOffset(3, 4, 3)
+	su, _ := camera.SampleOffset(3, 4, 2)
+
+	assert.Equal(t, []float64{u1, v1}, []float64{u2, v2})
+	assert.NotEqual(t, u1, u3)
+	assert.NotEqual(t, u1, su)
+
+	camera.Seed = 99
+	u4, _ := camera.LensOffset(3, 4, 2)
+	assert.NotEqual(t, u1, u4)
+}
+
+func TestDepthOfFieldBlursOutOfFocusObjects(t *testing.T) {
+	world := DefaultWorld()
+	camera := NewCamera(21, 21, math.Pi/2)
+	camera.Transform = ViewTransform(NewPoint(0, 0, -5), NewPoint(0, 0, 0), NewVector(0, 1, 0))
+	pinhole := camera.Render(world)
+
+	camera.Aperture = 0.5
+	camera.FocalDistance = 20
+	camera.Samples = 16
+	blurred := camera.Render(world)
+
+	// the silhouette of the sphere is spread out
+	assert.NotEqual(t, pinhole.ToPPM(), blurred.ToPPM())
+
+	camera.Workers = 1
+	assert.Equal(t, blurred.ToPPM(), camera.Render(world).ToPPM())
+}
+
+func TestSetViewKeepsSettings(t *testing.T) {
+	camera := NewCamera(10, 10, math.Pi/2)
+	camera.Aperture = 0.1
+	camera.Samples = 4
+	camera.Transform = camera.Transform.Translate(1, 2, 3)
+
+	camera.SetView(200, 125, math.Pi/2)
+
+	expected := NewCamera(200, 125, math.Pi/2)
+	assert.Equal(t, expected.pixelSize, camera.pixelSize)
+	assert.Equal(t, uint(200), camera.HSize())
+	assert.Equal(t, uint(125), camera.VSize())
+	assert.Equal(t, 0.1, camera.Aperture)
+	assert.Equal(t, uint(4), camera.Samples)
+	assert.Equal(t, NewIdentityMatrix().Translate(1, 2, 3), camera.Transform)
+}
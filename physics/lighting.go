@@ -6,44 +6,130 @@ import (
 	"math"
 )
 
+type Light interface {
+	GetIntensity() Color
+	// light the ambient term of a material is lit by
+	GetAmbient() Color
+	GetAttenuation() Attenuation
+	// light arriving at point, one sample per position on the light
+	Samples(point Tuple) []LightSample
+}
+
+type LightSample struct {
+	// unit vector from the point towards the light
+	Direction Tuple
+	// distance to the light, infinite for lights at infinity
+	Distance  float64
+	Intensity Color
+}
+
+func sampleFrom(position Tuple, point Tuple, intensity Color) LightSample {
+	vector := position.Subtract(point)
+
+	return LightSample{vector.Normalize(), vector.Magnitude(), intensity}
+}
+
 type PointLight struct {
-	position  Tuple
-	intensity Color
+	position    Tuple
+	intensity   Color
+	Attenuation Attenuation
 }
 
 func NewPointLight(position Tuple, intensity Color) *PointLight {
-	return &PointLight{position, intensity}
+	return &PointLight{position, intensity, Attenuation{}}
+}
+
+func (light PointLight) GetIntensity() Color {
+	return light.intensity
+}
+
+func (light PointLight) GetAmbient() Color {
+	return light.intensity
 }
 
-func Lighting(material Material, object Shape, light PointLight, point Tuple, eyev Tuple, normalv Tuple, inShadow bool) Color {
+func (light PointLight) GetAttenuation() Attenuation {
+	return light.Attenuation
+}
+
+func (light PointLight) Samples(point Tuple) []LightSample {
+	return []LightSample{sampleFrom(light.position, point, light.intensity)}
+}
+
+// intensity is the fraction of the light reaching point, 0 when fully in shadow
+func Lighting(material Material, object Shape, light Light, point Tuple, eyev Tuple, normalv Tuple, intensity float64) Color {
+	return lightingSamples(material, object, light, light.Samples(point), point, eyev, normalv, intensity)
+}
+
+// Lighting with samples already taken from light for point
+func lightingSamples(material Material, object Shape, light Light, samples []LightSample, point Tuple, eyev Tuple, normalv Tuple, intensity float64) Color {
+	var pbr PBRSample
 	var color Color
-	var diffuse Color
-	var specular Color
 
-	if material.Pattern != nil {
-		color = PatternColor(material.Pattern, object, point)
+	if material.PBR != nil {
+		pbr = material.PBR.At(object, point)
+		color = pbr.BaseColor
 	} else {
-		color = material.Color
+		color = SurfaceColor(material, object, point)
 	}
 
-	effectiveColor := color.Multiply(light.intensity)
-	lightv := light.position.Subtract(point).Normalize()
-	ambient := effectiveColor.MultiplyScalar(material.Ambient)
+	ambient := color.Multiply(light.GetAmbient()).MultiplyScalar(material.Ambient)
 
-	if !inShadow {
+	if intensity == 0 {
+		return ambient
+	}
+
+	var diffuse Color
+	var specular Color
+
+	attenuation := light.GetAttenuation()
+
+	for _, sample := range samples {
+		falloff := attenuation.Factor(sample.Distance)
+		if falloff == 0 {
+			continue
+		}
+
+		sampleIntensity := sample.Intensity.MultiplyScalar(falloff)
+		lightv := sample.Direction
 		lightDotNormal := lightv.Dot(normalv)
-		if lightDotNormal >= 0 {
-			diffuse = effectiveColor.MultiplyScalar(material.Diffuse).MultiplyScalar(lightDotNormal)
 
-			reflectv := lightv.Multiply(-1).Reflect(normalv)
-			reflectDotEye := reflectv.Dot(eyev)
+		if lightDotNormal < 0 {
+			continue
+		}
+
+		if material.PBR != nil {
+			diffuse = diffuse.Add(pbrReflectance(pbr, normalv, eyev, lightv).Multiply(sampleIntensity))
+			continue
+		}
+
+		effectiveColor := color.Multiply(sampleIntensity)
+		diffuse = diffuse.Add(effectiveColor.MultiplyScalar(material.Diffuse).MultiplyScalar(lightDotNormal))
+
+		reflectv := lightv.Multiply(-1).Reflect(normalv)
+		reflectDotEye := reflectv.Dot(eyev)
 
-			if reflectDotEye > 0 {
-				factor := math.Pow(reflectDotEye, material.Shininess)
-				specular = light.intensity.MultiplyScalar(material.Specular).MultiplyScalar(factor)
-			}
+		if reflectDotEye > 0 {
+			factor := math.Pow(reflectDotEye, material.Shininess)
+			specular = specular.Add(sampleIntensity.MultiplyScalar(material.Specular).MultiplyScalar(factor))
 		}
 	}
 
-	return ambient.Add(diffuse).Add(specular)
+	// average over the samples, scaled by how much of the light is visible
+	scale := intensity / float64(len(samples))
+
+	return ambient.Add(diffuse.MultiplyScalar(scale)).Add(specular.MultiplyScalar(scale))
+}
+
+// color of material at point, from its pattern when it has one or the
+// base color when it is a PBR material
+func SurfaceColor(material Material, object Shape, point Tuple) Color {
+	if material.PBR != nil {
+		return material.PBR.At(object, point).BaseColor
+	}
+
+	if material.Pattern != nil {
+		return PatternColor(material.Pattern, object, point)
+	}
+
+	return material.Color
 }
@@ -12,7 +12,7 @@ import (
 func TestPointLight(t *testing.T) {
 	intensity := White
 	position := NewPoint(0, 0, 0)
-	light := PointLight{position, intensity}
+	light := NewPointLight(position, intensity)
 
 	assert.Equal(t, position, light.position)
 	assert.Equal(t, intensity, light.intensity)
@@ -29,6 +29,18 @@ func TestDefaultMaterial(t *testing.T) {
 	assert.Equal(t, 0.0, material.Reflective)
 	assert.Equal(t, 0.0, material.Transparency)
 	assert.Equal(t, 1.0, material.RefractiveIndex)
+	assert.Equal(t, Black, material.Absorption)
+	assert.Equal(t, 1.0, material.AbsorptionDensity)
+}
+
+func TestMaterialTransmittance(t *testing.T) {
+	material := NewMaterial()
+	assert.Equal(t, White, material.Transmittance(10))
+
+	material.Absorption = NewColor(1, 0, 0.5)
+	material.AbsorptionDensity = 2
+
+	assert.Equal(t, NewColor(math.Exp(-1), 1, math.Exp(-0.5)), material.Transmittance(0.5))
 }
 
 func TestLightingWithEyeBetweenLightAndSurface(t *testing.T) {
@@ -36,9 +48,9 @@ func TestLightingWithEyeBetweenLightAndSurface(t *testing.T) {
 	position := NewPoint(0, 0, 0)
 	eyev := NewVector(0, 0, -1)
 	normalv := NewVector(0, 0, -1)
-	light := PointLight{NewPoint(0, 0, -10), White}
+	light := NewPointLight(NewPoint(0, 0, -10), White)
 
-	result := Lighting(material, NewSphere(), light, position, eyev, normalv, false)
+	result := Lighting(material, NewSphere(), light, position, eyev, normalv, 1.0)
 
 	assert.Equal(t, NewColor(1.9, 1.9, 1.9), result)
 }
@@ -48,9 +60,9 @@ func TestLightingWithEyeBetweenLightAndSurfaceAndEyeOffset45(t *testing.T) {
 	position := NewPoint(0, 0, 0)
 	eyev := NewVector(0, math.Sqrt2/2, -math.Sqrt2/2)
 	normalv := NewVector(0, 0, -1)
-	light := PointLight{NewPoint(0, 0, -10), White}
+	light := NewPointLight(NewPoint(0, 0, -10), White)
 
-	result := Lighting(material, NewSphere(), light, position, eyev, normalv, false)
+	result := Lighting(material, NewSphere(), light, position, eyev, normalv, 1.0)
 
 	assert.Equal(t, White, result)
 }
@@ -60,9 +72,9 @@ func TestLightingWithEyeOppositeSurfaceAndEyeOffset45(t *testing.T) {
 	position := NewPoint(0, 0, 0)
 	eyev := NewVector(0, 0, -1)
 	normalv := NewVector(0, 0, -1)
-	light := PointLight{NewPoint(0, 10, -10), White}
+	light := NewPointLight(NewPoint(0, 10, -10), White)
 
-	result := Lighting(material, NewSphere(), light, position, eyev, normalv, false)
+	result := Lighting(material, NewSphere(), light, position, eyev, normalv, 1.0)
 
 	EqualColor(t, NewColor(0.7364, 0.7364, 0.7364), result)
 }
@@ -72,9 +84,9 @@ func TestLightingWithEyeInPathOfReflectionVector(t *testing.T) {
 	position := NewPoint(0, 0, 0)
 	eyev := NewVector(0, -math.Sqrt2/2, -math.Sqrt2/2)
 	normalv := NewVector(0, 0, -1)
-	light := PointLight{NewPoint(0, 10, -10), White}
+	light := NewPointLight(NewPoint(0, 10, -10), White)
 
-	result := Lighting(material, NewSphere(), light, position, eyev, normalv, false)
+	result := Lighting(material, NewSphere(), light, position, eyev, normalv, 1.0)
 
 	EqualColor(t, NewColor(1.6364, 1.6364, 1.6364), result)
 }
@@ -84,9 +96,9 @@ func TestLightingWithLightBehindSurface(t *testing.T) {
 	position := NewPoint(0, 0, 0)
 	eyev := NewVector(0, 0, -1)
 	normalv := NewVector(0, 0, -1)
-	light := PointLight{NewPoint(0, 0, 10), White}
+	light := NewPointLight(NewPoint(0, 0, 10), White)
 
-	result := Lighting(material, NewSphere(), light, position, eyev, normalv, false)
+	result := Lighting(material, NewSphere(), light, position, eyev, normalv, 1.0)
 
 	assert.Equal(t, NewColor(0.1, 0.1, 0.1), result)
 }
@@ -96,10 +108,10 @@ func TestLightingWithSurfaceInShadow(t *testing.T) {
 	position := NewPoint(0, 0, 0)
 	eyev := NewVector(0, 0, -1)
 	normalv := NewVector(0, 0, -1)
-	light := PointLight{NewPoint(0, 0, -10), White}
-	inShadow := true
+	light := NewPointLight(NewPoint(0, 0, -10), White)
+	intensity := 0.0
 
-	result := Lighting(material, NewSphere(), light, position, eyev, normalv, inShadow)
+	result := Lighting(material, NewSphere(), light, position, eyev, normalv, intensity)
 
 	assert.Equal(t, NewColor(0.1, 0.1, 0.1), result)
 }
@@ -114,11 +126,11 @@ func TestLightingWithPatternApplied(t *testing.T) {
 
 	eyev := NewVector(0, 0, -1)
 	normalv := NewVector(0, 0, -1)
-	light := PointLight{NewPoint(0, 0, -10), White}
-	inShadow := false
+	light := NewPointLight(NewPoint(0, 0, -10), White)
+	intensity := 1.0
 
-	c1 := Lighting(material, NewSphere(), light, NewPoint(0.9, 0, 0), eyev, normalv, inShadow)
-	c2 := Lighting(material, NewSphere(), light, NewPoint(1.1, 0, 0), eyev, normalv, inShadow)
+	c1 := Lighting(material, NewSphere(), light, NewPoint(0.9, 0, 0), eyev, normalv, intensity)
+	c2 := Lighting(material, NewSphere(), light, NewPoint(1.1, 0, 0), eyev, normalv, intensity)
 
 	assert.Equal(t, White, c1)
 	assert.Equal(t, Black, c2)
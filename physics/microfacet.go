@@ -0,0 +1,124 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"math"
+)
+
+// keeps the GGX distribution finite for perfectly smooth surfaces
+const minAlpha = 1e-3
+
+// width of the GGX distribution, the square of roughness
+func ggxAlpha(roughness float64) float64 {
+	return math.Max(roughness*roughness, minAlpha)
+}
+
+// GGX distribution of microfacet normals, the density of those at an angle
+// with cosine nDotH to the surface normal
+func ggxDistribution(nDotH float64, alpha float64) float64 {
+	a2 := alpha * alpha
+	d := nDotH*nDotH*(a2-1) + 1
+
+	return a2 / (math.Pi * d * d)
+}
+
+// fraction of microfacets seen from a direction with cosine nDotX to the
+// normal that are not hidden by others
+func smithG1(nDotX float64, alpha float64) float64 {
+	a2 := alpha * alpha
+
+	return 2 * nDotX / (nDotX + math.Sqrt(a2+(1-a2)*nDotX*nDotX))
+}
+
+// shadowing towards the light and masking towards the eye together
+func smithG(nDotL float64, nDotV float64, alpha float64) float64 {
+	return smithG1(nDotL, alpha) * smithG1(nDotV, alpha)
+}
+
+// Schlick's approximation of the reflectance at an angle with cosine cos,
+// rising from f0 head on to white at grazing angles
+func fresnelSchlick(f0 Color, cos float64) Color {
+	return f0.Add(White.Subtract(f0).MultiplyScalar(math.Pow(1-math.Max(cos, 0), 5)))
+}
+
+// light reflected towards eyev for light arriving along lightv, with a
+// Lambert diffuse term and a GGX specular term. Scaled by pi, like the
+// diffuse term of Lighting, so a white light on a white diffuse surface
+// facing it gives white
+func pbrReflectance(sample PBRSample, normalv Tuple, eyev Tuple, lightv Tuple) Color {
+	nDotL := normalv.Dot(lightv)
+	nDotV := normalv.Dot(eyev)
+	if nDotL <= 0 || nDotV <= 0 {
+		return Black
+	}
+
+	halfv := lightv.Add(eyev).Normalize()
+	alpha := ggxAlpha(sample.Roughness)
+	fresnel := fresnelSchlick(sample.F0(), halfv.Dot(eyev))
+
+	specular := fresnel.MultiplyScalar(
+		ggxDistribution(normalv.Dot(halfv), alpha) * smithG(nDotL, nDotV, alpha) / (4 * nDotL * nDotV))
+	diffuse := White.Subtract(fresnel).Multiply(sample.BaseColor).MultiplyScalar((1 - sample.Metallic) / math.Pi)
+
+	return diffuse.Add(specular).MultiplyScalar(math.Pi * nDotL)
+}
+
+// microfacet normal from the GGX distribution around normal for u and v
+// from 0 to 1, more likely the closer it is to normal
+func ggxHalfVector(normal Tuple, alpha float64, u float64, v float64) Tuple {
+	phi := 2 * math.Pi * u
+	cosTheta := math.Sqrt((1 - v) / (1 + (alpha*alpha-1)*v))
+	sinTheta := math.Sqrt(math.Max(0, 1-cosTheta*cosTheta))
+	tangent, bitangent := orthonormalBasis(normal)
+
+	return tangent.Multiply(sinTheta * math.Cos(phi)).
+		Add(bitangent.Multiply(sinTheta * math.Sin(phi))).
+		Add(normal.Multiply(cosTheta))
+}
+
+// direction of a ray from eyev reflected off a microfacet picked for u and
+// v, and the light it carries back as a fraction of the light arriving
+// along it, black when the reflection points into the surface
+func ggxReflection(sample PBRSample, normalv Tuple, eyev Tuple, u float64, v float64) (Tuple, Color) {
+	alpha := ggxAlpha(sample.Roughness)
+	halfv := ggxHalfVector(normalv, alpha, u, v)
+	vDotH := eyev.Dot(halfv)
+	direction := halfv.Multiply(2 * vDotH).Subtract(eyev)
+
+	nDotL := normalv.Dot(direction)
+	nDotV := normalv.Dot(eyev)
+	nDotH := normalv.Dot(halfv)
+	if nDotL <= 0 || nDotV <= 0 || vDotH <= 0 {
+		return direction, Black
+	}
+
+	// the distribution cancels against the chance of picking the microfacet
+	weight := smithG(nDotL, nDotV, alpha) * vDotH / (nDotV * nDotH)
+
+	return direction, fresnelSchlick(sample.F0(), vDotH).MultiplyScalar(weight)
+}
+
+// direction of a ray from eyev reflected off the microfacet picked for u
+// and v, spread further from the mirror direction the rougher the surface,
+// false when it points into the surface
+func roughReflection(normalv Tuple, eyev Tuple, roughness float64, u float64, v float64) (Tuple, bool) {
+	halfv := ggxHalfVector(normalv, ggxAlpha(roughness), u, v)
+	direction := halfv.Multiply(2 * eyev.Dot(halfv)).Subtract(eyev)
+
+	return direction, direction.Dot(normalv) > 0
+}
+
+// direction of a ray from eyev refracted through the microfacet picked for
+// u and v, false on total internal reflection or when it leaves on the
+// side it came from
+func roughRefraction(normalv Tuple, eyev Tuple, nRatio float64, roughness float64, u float64, v float64) (Tuple, bool) {
+	halfv := ggxHalfVector(normalv, ggxAlpha(roughness), u, v)
+	if eyev.Dot(halfv) <= 0 {
+		return Tuple{}, false
+	}
+
+	direction, refracts := refract(eyev, halfv, nRatio)
+
+	return direction, refracts && direction.Dot(normalv) < 0
+}
@@ -0,0 +1,159 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGGXDistributionIsNormalized(t *testing.T) {
+	for _, alpha := range []float64{0.1, 0.5, 1} {
+		const steps = 100000
+		total := 0.0
+
+		// projected area of the microfacets adds up to the area of the surface
+		for i := 0; i < steps; i++ {
+			theta := (float64(i) + 0.5) / steps * math.Pi / 2
+			total += ggxDistribution(math.Cos(theta), alpha) * math.Cos(theta) * math.Sin(theta)
+		}
+
+		assert.InDelta(t, 1, total*2*math.Pi*math.Pi/2/steps, 1e-3, alpha)
+	}
+}
+
+func TestSmithAndFresnelLimits(t *testing.T) {
+	assert.InDelta(t, 1, smithG1(1, 0.5), 1e-12)
+	assert.Less(t, smithG1(0.1, 0.5), 1.0)
+
+	f0 := NewColor(0.04, 0.5, 1)
+	assert.Equal(t, f0, fresnelSchlick(f0, 1))
+	assert.Equal(t, White, fresnelSchlick(f0, 0))
+}
+
+func TestPBRReflectance(t *testing.T) {
+	normalv := NewVector(0, 1, 0)
+	eyev := NewVector(0, 1, 0)
+
+	below := pbrReflectance(PBRSample{BaseColor: White, Roughness: 0.5, Specular: 0.5}, normalv, eyev, NewVector(0, -1, 0))
+	assert.Equal(t, Black, below)
+
+	// metals have no diffuse light, so a red metal only reflects green
+	// through the Fresnel term at grazing angles
+	metal := pbrReflectance(PBRSample{BaseColor: Red, Metallic: 1, Roughness: 1, Specular: 0.5}, normalv, eyev, NewVector(1, 1, 0).Normalize())
+	assert.Greater(t, metal.Red, 0.0)
+	assert.Less(t, metal.Green, metal.Red*0.001)
+
+	// a rough white dielectric lit head on is close to white
+	matte := pbrReflectance(PBRSample{BaseColor: White, Roughness: 1, Specular: 0.5}, normalv, eyev, normalv)
+	assert.InDelta(t, 1, matte.Red, 0.05)
+}
+
+func TestGGXReflectionOfSmoothSurfaceIsMirror(t *testing.T) {
+	normalv := NewVector(0, 1, 0)
+	eyev := NewVector(-1, 1, 0).Normalize()
+	sample := PBRSample{BaseColor: Red, Metallic: 1, Roughness: 0, Specular: 0.5}
+
+	direction, weight := ggxReflection(sample, normalv, eyev, 0.3, 0.7)
+
+	assert.InDelta(t, 1, NewVector(1, 1, 0).Normalize().Dot(direction), 1e-4)
+	assert.InDelta(t, 1, weight.Red, 0.01)
+	assert.InDelta(t, fresnelSchlick(Red, math.Sqrt2/2).Green, weight.Green, 1e-3)
+}
+
+func TestLightingPBR(t *testing.T) {
+	material := NewMaterial()
+	material.PBR = NewPBR(NewColor(0.8, 0.2, 0.2), 0, 0.4)
+	object := NewSphere()
+	light := NewPointLight(NewPoint(0, 10, -10), White)
+	point := NewPoint(0, 0, -1)
+	eyev := NewVector(0, 0, -1)
+	normalv := NewVector(0, 0, -1)
+
+	color := Lighting(material, object, light, point, eyev, normalv, 1)
+
+	ambient := NewColor(0.8, 0.2, 0.2).MultiplyScalar(0.1)
+	reflected := pbrReflectance(material.PBR.At(object, point), normalv, eyev, NewVector(0, 10, -9).Normalize())
+	EqualColor(t, ambient.Add(reflected), color)
+
+	EqualColor(t, ambient, Lighting(material, object, light, point, eyev, normalv, 0))
+}
+
+func TestRoughMetalBlursReflections(t *testing.T) {
+	// enough rays for the rough reflection to settle
+	world := World{GlossySamples: 1024}
+	world.Lights = []Light{NewPointLight(NewPoint(0, 10, 0), White)}
+	metal := NewPlane()
+	metal.Material.PBR = NewPBR(White, 1, 0)
+	metal.Material.Ambient = 0
+	ceiling := NewPlane()
+	ceiling.Transform = ceiling.Transform.Translate(0, 3, 0)
+	ceiling.Material.Ambient = 1
+	world.Objects = []Shape{metal, ceiling}
+	ray := NewRay(NewPoint(1.5, 1, -0.5), NewVector(0, -1, 1).Normalize())
+
+	// the reflection meets the ceiling at (1.5, 3, 3.5)
+	assert.Equal(t, White, world.ColorAt(ray, 4))
+	assert.Equal(t, Black, world.ColorAt(ray, 0))
+
+	// masking between the microfacets keeps a rough metal from reflecting
+	// all of a plain white ceiling
+	metal.Material.PBR = NewPBR(White, 1, 0.6)
+	plain := world.ColorAt(ray, 4)
+	assert.InDelta(t, 0.79, plain.Red, 0.03)
+
+	// that point is in a white square, a rough reflection averages it with
+	// the black squares around it
+	ceiling.Material.Pattern = NewCheckersPattern(NewSolidPattern(White), NewSolidPattern(Black))
+	metal.Material.PBR = NewPBR(White, 1, 0)
+	assert.Equal(t, White, world.ColorAt(ray, 4))
+
+	metal.Material.PBR = NewPBR(White, 1, 0.6)
+	assert.InDelta(t, plain.Red/2, world.ColorAt(ray, 4).Red, 0.02)
+}
+
+func TestPathTracerPBR(t *testing.T) {
+	world := World{}
+	metal := NewPlane()
+	metal.Material.PBR = NewPBR(White, 1, 0)
+	// the path tracer has no ambient light, so the ceiling glows instead
+	ceiling := NewPlane()
+	ceiling.Transform = ceiling.Transform.Translate(0, 3, 0)
+	ceiling.Material.Pattern = NewCheckersPattern(NewSolidPattern(White), NewSolidPattern(Black))
+	ceiling.Material.Ambient = 0
+	ceiling.Material.Diffuse = 0
+	ceiling.Material.Emission = White
+	world.Objects = []Shape{metal, ceiling}
+	ray := NewRay(NewPoint(1.5, 1, -0.5), NewVector(0, -1, 1).Normalize())
+	tracer := NewPathTracer()
+
+	assert.Equal(t, White, tracer.Trace(world, ray, 4, NewSampler(1)))
+
+	metal.Material.PBR = NewPBR(White, 1, 0.6)
+	color := Black
+	for i := uint64(0); i < 1000; i++ {
+		color = color.Add(tracer.Trace(world, ray, 4, NewSampler(i)))
+	}
+
+	assert.InDelta(t, world.ColorAt(ray, 4).Red, color.Red/1000, 0.05)
+}
+
+func TestRoughDirectionsOfSmoothSurface(t *testing.T) {
+	normalv := NewVector(0, 1, 0)
+	eyev := NewVector(-1, 1, 0).Normalize()
+
+	reflected, reflects := roughReflection(normalv, eyev, 0, 0.3, 0.7)
+	assert.True(t, reflects)
+	assert.InDelta(t, 1, NewVector(1, 1, 0).Normalize().Dot(reflected), 1e-4)
+
+	expected, _ := refract(eyev, normalv, 1/1.5)
+	refracted, refracts := roughRefraction(normalv, eyev, 1/1.5, 0, 0.3, 0.7)
+	assert.True(t, refracts)
+	assert.InDelta(t, 1, expected.Dot(refracted), 1e-4)
+
+	// leaving glass at a grazing angle is totally reflected
+	_, refracts = roughRefraction(normalv, NewVector(-1, 0.1, 0).Normalize(), 1.5, 0, 0.3, 0.7)
+	assert.False(t, refracts)
+}
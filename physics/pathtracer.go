@@ -0,0 +1,203 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"math"
+)
+
+// Monte Carlo path tracing, each path bounces off surfaces in random
+// directions so indirect light and color bleeding show up, with noise that
+// fades as camera.Samples grows. Lights are sampled directly at every
+// bounce and the ambient term is not used
+type PathTracer struct {
+	// bounces before paths may end at random, with paths carrying less
+	// light more likely to end
+	RouletteDepth uint
+}
+
+func NewPathTracer() PathTracer {
+	return PathTracer{RouletteDepth: 3}
+}
+
+func (tracer PathTracer) Trace(world World, ray Ray, depth uint, sampler *Sampler) Color {
+	color := Black
+	throughput := White
+	kind := specularBounce
+
+	for bounce := uint(0); ; bounce++ {
+		intersections := world.Intersect(ray)
+		hit, err := Hit(intersections)
+		if err != nil {
+			return color
+		}
+
+		comps := PrepareComputations(hit, ray, intersections)
+		throughput = throughput.Multiply(comps.Transmittance())
+
+		// after a diffuse bounce the light of shape lights has already
+		// been counted by sampling them directly, apart from the light of
+		// their back sides, which they do not sample
+		if kind == specularBounce || comps.inside || !world.isShapeLight(comps.object) {
+			color = color.Add(throughput.Multiply(comps.object.GetMaterial().Emitted()))
+		}
+
+		color = color.Add(throughput.Multiply(world.directLight(comps)))
+
+		if bounce >= depth {
+			return color
+		}
+
+		if bounce >= tracer.RouletteDepth {
+			// surviving paths carry more light so the average is unchanged
+			survival := math.Min(maxChannel(throughput), 0.95)
+			if sampler.Float() >= survival {
+				return color
+			}
+
+			throughput = throughput.MultiplyScalar(1 / survival)
+		}
+
+		var weight Color
+
+		ray, weight, kind = world.scatter(comps, sampler)
+		if kind == absorbed {
+			return color
+		}
+
+		throughput = throughput.Multiply(weight)
+	}
+}
+
+// diffuse and specular light reaching the hit straight from the lights
+func (world World) directLight(comps Comps) Color {
+	material := comps.object.GetMaterial()
+	material.Ambient = 0
+	color := Black
+
+	for _, light := range world.Lights {
+		color = color.Add(world.lightAt(material, comps, light))
+	}
+
+	return color
+}
+
+// whether object is the shape of a ShapeLight in world or part of one
+func (world World) isShapeLight(object Shape) bool {
+	for _, light := range world.Lights {
+		shapeLight, ok := light.(*ShapeLight)
+		if !ok {
+			continue
+		}
+
+		for shape := object; shape != nil; shape = shape.GetParent() {
+			if shape == shapeLight.GetShape() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// kinds of bounce a path takes off a surface
+type scatterKind int
+
+const (
+	absorbed scatterKind = iota
+	diffuseBounce
+	specularBounce
+)
+
+// picks a diffuse bounce, a mirror reflection or a refraction in
+// proportion to how much the material does of each, the weight is the
+// fraction of light the new ray carries back
+func (world World) scatter(comps Comps, sampler *Sampler) (Ray, Color, scatterKind) {
+	material := comps.object.GetMaterial()
+	if material.PBR != nil {
+		return scatterPBR(comps, material.PBR.At(comps.object, comps.point), sampler)
+	}
+
+	reflective, transparency := material.Reflective, material.Transparency
+
+	// the same split as ShadeHit
+	if reflective > 0 && transparency > 0 {
+		reflectance := comps.Schlick()
+		reflective *= reflectance
+		transparency *= 1 - reflectance
+	}
+
+	total := material.Diffuse + reflective + transparency
+	if total <= 0 {
+		return Ray{}, Black, absorbed
+	}
+
+	// dividing by the chance of each choice leaves total as the weight
+	choice := sampler.Float() * total
+	weight := White.MultiplyScalar(total)
+
+	if choice < material.Diffuse {
+		// cosine weighted directions cancel the cosine in the diffuse term
+		direction := cosineHemisphere(comps.normalv, sampler.Float(), sampler.Float())
+		color := SurfaceColor(material, comps.object, comps.point)
+
+		return NewRay(comps.overPoint, direction), color.MultiplyScalar(total), diffuseBounce
+	}
+
+	if choice >= material.Diffuse+reflective {
+		direction, refracts := comps.RefractedDirection()
+		if material.Roughness > 0 {
+			nRatio := comps.n1 / comps.n2
+			direction, refracts = roughRefraction(comps.normalv, comps.eyev, nRatio, material.Roughness, sampler.Float(), sampler.Float())
+		}
+
+		if refracts {
+			return NewRay(comps.underPoint, direction), weight, specularBounce
+		}
+	}
+
+	if material.Roughness > 0 {
+		direction, reflects := roughReflection(comps.normalv, comps.eyev, material.Roughness, sampler.Float(), sampler.Float())
+		if !reflects {
+			return Ray{}, Black, absorbed
+		}
+
+		return NewRay(comps.overPoint, direction), weight, specularBounce
+	}
+
+	return NewRay(comps.overPoint, comps.reflectv), weight, specularBounce
+}
+
+// picks a reflection off the GGX lobe or a diffuse bounce, metals always
+// reflect and dielectrics do either half of the time
+func scatterPBR(comps Comps, sample PBRSample, sampler *Sampler) (Ray, Color, scatterKind) {
+	chance := (1 + sample.Metallic) / 2
+
+	if sampler.Float() < chance {
+		if sample.Roughness == 0 {
+			fresnel := fresnelSchlick(sample.F0(), comps.eyev.Dot(comps.normalv))
+
+			return NewRay(comps.overPoint, comps.reflectv), fresnel.MultiplyScalar(1 / chance), specularBounce
+		}
+
+		direction, weight := ggxReflection(sample, comps.normalv, comps.eyev, sampler.Float(), sampler.Float())
+		if weight == Black {
+			return Ray{}, Black, absorbed
+		}
+
+		// lights are sampled directly through the same lobe
+		return NewRay(comps.overPoint, direction), weight.MultiplyScalar(1 / chance), diffuseBounce
+	}
+
+	// light not reflected by the Fresnel term enters the diffuse layer
+	fresnel := fresnelSchlick(sample.F0(), comps.eyev.Dot(comps.normalv))
+	weight := White.Subtract(fresnel).Multiply(sample.BaseColor).MultiplyScalar((1 - sample.Metallic) / (1 - chance))
+	direction := cosineHemisphere(comps.normalv, sampler.Float(), sampler.Float())
+
+	return NewRay(comps.overPoint, direction), weight, diffuseBounce
+}
+
+// brightest channel of a color
+func maxChannel(color Color) float64 {
+	return math.Max(color.Red, math.Max(color.Green, color.Blue))
+}
@@ -0,0 +1,114 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCameraDefaultsToWhitted(t *testing.T) {
+	camera := NewCamera(11, 11, math.Pi/2)
+
+	assert.Equal(t, WhittedIntegrator{}, camera.Integrator)
+}
+
+func TestPathTracerMissIsBlack(t *testing.T) {
+	world := DefaultWorld()
+	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 1, 0))
+
+	assert.Equal(t, Black, NewPathTracer().Trace(world, ray, 4, NewSampler(1)))
+}
+
+func TestPathTracerWithoutBouncesIsDirectLight(t *testing.T) {
+	world := DefaultWorld()
+	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+
+	color := NewPathTracer().Trace(world, ray, 0, NewSampler(1))
+
+	// ShadeHit without the ambient term
+	EqualColor(t, NewColor(0.30066, 0.37583, 0.2255), color)
+}
+
+func TestPathTracerFollowsMirrors(t *testing.T) {
+	world := DefaultWorld()
+	world.Lights = []Light{NewPointLight(NewPoint(0, 0.25, 0), White)}
+	mirror := NewPlane()
+	mirror.Material.Diffuse = 0
+	mirror.Material.Specular = 0
+	mirror.Material.Reflective = 1
+	mirror.Transform = mirror.Transform.Translate(0, -1, 0)
+	world.Objects = []Shape{mirror}
+	ray := NewRay(NewPoint(0, 0, -3), NewVector(0, -math.Sqrt2/2, math.Sqrt2/2))
+
+	// a perfect mirror of nothing but the black sky
+	assert.Equal(t, Black, NewPathTracer().Trace(world, ray, 4, NewSampler(1)))
+}
+
+func TestPathTracerColorBleeding(t *testing.T) {
+	world := World{}
+	world.Lights = []Light{NewPointLight(NewPoint(-2, 5, -2), White)}
+	floor := NewPlane()
+	wall := NewPlane()
+	wall.Transform = wall.Transform.Translate(1, 0, 0).RotateZ(math.Pi / 2)
+	wall.Material.Color = Red
+	wall.Material.Specular = 0
+	world.Objects = []Shape{floor, wall}
+	ray := NewRay(NewPoint(0.5, 1, -1), NewVector(0, -1, 1).Normalize())
+
+	tracer := NewPathTracer()
+	direct := tracer.Trace(world, ray, 0, NewSampler(1))
+
+	// diffuse light at (0.5, 0, 0), the highlight is too far off to count
+	toLight := NewVector(-2.5, 5, -2)
+	EqualColor(t, White.MultiplyScalar(0.9*toLight.Y/toLight.Magnitude()), direct)
+
+	color := Black
+	for i := uint64(0); i < 500; i++ {
+		color = color.Add(tracer.Trace(world, ray, 4, NewSampler(i)))
+	}
+	color = color.MultiplyScalar(1.0 / 500)
+
+	// only red comes back from the red wall, 20000 paths put it at 0.397
+	assert.InDelta(t, direct.Green, color.Green, 1e-9)
+	assert.InDelta(t, direct.Blue, color.Blue, 1e-9)
+	assert.InDelta(t, 0.397, color.Red-direct.Red, 0.03)
+}
+
+func TestPathTracerRouletteEndsPaths(t *testing.T) {
+	world := World{}
+	world.Lights = []Light{NewPointLight(NewPoint(0, 0, 0), White)}
+	mirrors := NewSphere()
+	mirrors.Transform = mirrors.Transform.Scale(10, 10, 10)
+	mirrors.Material.Diffuse = 0
+	mirrors.Material.Reflective = 1
+	world.Objects = []Shape{mirrors}
+	ray := NewRay(NewPoint(0, 0, 0), NewVector(0, 0, 1))
+
+	tracer := PathTracer{RouletteDepth: 0}
+	color := tracer.Trace(world, ray, ^uint(0), NewSampler(3))
+
+	assert.False(t, math.IsInf(color.Red, 0) || math.IsNaN(color.Red))
+}
+
+func TestPathTracedRenderIsReproducible(t *testing.T) {
+	world := DefaultWorld()
+	floor := NewPlane()
+	floor.Transform = floor.Transform.Translate(0, -1, 0)
+	world.Objects = append(world.Objects, floor)
+	camera := NewCamera(11, 11, math.Pi/2)
+	camera.Transform = ViewTransform(NewPoint(0, 1, -5), NewPoint(0, 0, 0), NewVector(0, 1, 0))
+	camera.Integrator = NewPathTracer()
+	camera.Samples = 4
+	camera.Workers = 1
+
+	serial := camera.Render(world)
+	camera.Workers = 4
+
+	assert.Equal(t, serial, camera.Render(world))
+
+	camera.Seed = 1
+	assert.NotEqual(t, serial, camera.Render(world))
+}
@@ -0,0 +1,93 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	"math"
+)
+
+// maps points on the image to rays in camera space, where the camera looks
+// towards -z with +y up and +x towards the left edge of the image
+type Projection interface {
+	// start of the ray through image point (x, y) and a second point on it,
+	// x and y are in pixels from the top left corner and (lensX, lensY) is
+	// the point on the aperture the ray leaves from, false where the
+	// projection does not cover the image point
+	CameraRay(camera *Camera, x float64, y float64, lensX float64, lensY float64) (Tuple, Tuple, bool)
+}
+
+// rays spread from the camera through a plane one unit in front of it,
+// camera.FieldOfView is the angle across the longer side of the image
+type PerspectiveProjection struct{}
+
+func (projection PerspectiveProjection) CameraRay(camera *Camera, x float64, y float64, lensX float64, lensY float64) (Tuple, Tuple, bool) {
+	worldX := camera.halfWidth - x*camera.pixelSize
+	worldY := camera.halfHeight - y*camera.pixelSize
+
+	if lensX == 0 && lensY == 0 {
+		return NewPoint(0, 0, 0), NewPoint(worldX, worldY, -1), true
+	}
+
+	// rays from every point on the lens meet again on the focal plane
+	distance := camera.FocalDistance
+
+	return NewPoint(lensX, lensY, 0), NewPoint(worldX*distance, worldY*distance, -distance), true
+}
+
+// parallel rays from a rectangle Width units across, so sizes do not
+// change with distance, the field of view is not used
+type OrthographicProjection struct {
+	Width float64
+}
+
+func (projection OrthographicProjection) CameraRay(camera *Camera, x float64, y float64, lensX float64, lensY float64) (Tuple, Tuple, bool) {
+	pixelSize := projection.Width / float64(camera.hsize)
+
+	worldX := projection.Width/2 - x*pixelSize
+	worldY := pixelSize*float64(camera.vsize)/2 - y*pixelSize
+
+	return NewPoint(worldX+lensX, worldY+lensY, 0), NewPoint(worldX, worldY, -camera.FocalDistance), true
+}
+
+// equidistant fisheye, the angle from the view direction grows evenly
+// towards the edge of a circle that fills the shorter side of the image,
+// where it reaches half of camera.FieldOfView. The aperture is not used
+type FisheyeProjection struct{}
+
+func (projection FisheyeProjection) CameraRay(camera *Camera, x float64, y float64, lensX float64, lensY float64) (Tuple, Tuple, bool) {
+	radius := math.Min(float64(camera.hsize), float64(camera.vsize)) / 2
+	u := (float64(camera.hsize)/2 - x) / radius
+	v := (float64(camera.vsize)/2 - y) / radius
+
+	distance := math.Hypot(u, v)
+	if distance > 1 {
+		return Tuple{}, Tuple{}, false
+	}
+
+	if distance == 0 {
+		return NewPoint(0, 0, 0), NewPoint(0, 0, -1), true
+	}
+
+	theta := distance * camera.fieldOfView / 2
+	sin := math.Sin(theta) / distance
+
+	return NewPoint(0, 0, 0), NewPoint(u*sin, v*sin, -math.Cos(theta)), true
+}
+
+// full 360 by 180 degree panorama, longitude runs across the image with the
+// view direction in the middle and latitude runs from straight up at the
+// top to straight down at the bottom. The field of view and the aperture
+// are not used
+type EquirectangularProjection struct{}
+
+func (projection EquirectangularProjection) CameraRay(camera *Camera, x float64, y float64, lensX float64, lensY float64) (Tuple, Tuple, bool) {
+	longitude := (x/float64(camera.hsize) - 0.5) * 2 * math.Pi
+	latitude := (0.5 - y/float64(camera.vsize)) * math.Pi
+
+	direction := NewPoint(
+		-math.Sin(longitude)*math.Cos(latitude),
+		math.Sin(latitude),
+		-math.Cos(longitude)*math.Cos(latitude),
+	)
+
+	return NewPoint(0, 0, 0), direction, true
+}
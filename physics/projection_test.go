@@ -0,0 +1,132 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCameraDefaultsToPerspective(t *testing.T) {
+	camera := NewCamera(201, 101, math.Pi/2)
+	assert.Equal(t, PerspectiveProjection{}, camera.Projection)
+
+	camera.Projection = nil
+	ray := camera.RayForPixel(0, 0)
+
+	EqualTuple(t, NewPoint(0, 0, 0), ray.Origin)
+	EqualTuple(t, NewVector(0.66519, 0.33259, -0.66851), ray.Direction)
+}
+
+func TestOrthographicRaysAreParallel(t *testing.T) {
+	camera := NewCamera(200, 100, math.Pi/2)
+	camera.Projection = OrthographicProjection{Width: 4}
+
+	center := camera.RayForPixelOffset(100, 50, 0, 0)
+	EqualTuple(t, NewPoint(0, 0, 0), center.Origin)
+	EqualTuple(t, NewVector(0, 0, -1), center.Direction)
+
+	corner := camera.RayForPixelOffset(0, 0, 0, 0)
+	EqualTuple(t, NewPoint(2, 1, 0), corner.Origin)
+	EqualTuple(t, NewVector(0, 0, -1), corner.Direction)
+
+	pixel := camera.RayForPixel(199, 99)
+	EqualTuple(t, NewPoint(-1.99, -0.99, 0), pixel.Origin)
+	EqualTuple(t, NewVector(0, 0, -1), pixel.Direction)
+}
+
+func TestOrthographicUsesCameraTransform(t *testing.T) {
+	camera := NewCamera(200, 100, math.Pi/2)
+	camera.Projection = OrthographicProjection{Width: 4}
+	camera.Transform = camera.Transform.RotateY(math.Pi/2).Translate(0, -2, 5)
+
+	ray := camera.RayForPixelOffset(100, 50, 0, 0)
+
+	EqualTuple(t, NewPoint(0, 2, -5), ray.Origin)
+	EqualTuple(t, NewVector(1, 0, 0), ray.Direction)
+}
+
+func TestOrthographicLensRaysMeetOnFocalPlane(t *testing.T) {
+	camera := NewCamera(200, 100, math.Pi/2)
+	camera.Projection = OrthographicProjection{Width: 4}
+	camera.Aperture = 0.5
+	camera.FocalDistance = 3
+
+	pinhole := focalPlanePoint(camera.RayForPixel(30, 70), 3)
+	lens := camera.RayThroughLens(30, 70, 0.5, 0.5, 0.9, 0.2)
+
+	assert.False(t, lens.Origin.Equals(camera.RayForPixel(30, 70).Origin))
+	EqualTuple(t, pinhole, focalPlanePoint(lens, 3))
+}
+
+func TestFisheyeProjection(t *testing.T) {
+	camera := NewCamera(200, 100, math.Pi)
+	camera.Projection = FisheyeProjection{}
+
+	tests := []struct {
+		x, y      float64
+		direction Tuple
+	}{
+		{100, 50, NewVector(0, 0, -1)},
+		{50, 50, NewVector(1, 0, 0)},
+		{150, 50, NewVector(-1, 0, 0)},
+		{100, 0, NewVector(0, 1, 0)},
+		{75, 50, NewVector(math.Sqrt2/2, 0, -math.Sqrt2/2)},
+	}
+
+	for _, test := range tests {
+		ray, covered := camera.imageRay(test.x, test.y, 0, 0)
+
+		assert.True(t, covered)
+		EqualTuple(t, NewPoint(0, 0, 0), ray.Origin)
+		EqualTuple(t, test.direction, ray.Direction)
+	}
+
+	_, covered := camera.imageRay(0, 0, 0, 0)
+	assert.False(t, covered)
+}
+
+func TestEquirectangularProjection(t *testing.T) {
+	camera := NewCamera(200, 100, math.Pi/2)
+	camera.Projection = EquirectangularProjection{}
+
+	tests := []struct {
+		x, y      float64
+		direction Tuple
+	}{
+		{100, 50, NewVector(0, 0, -1)},
+		{50, 50, NewVector(1, 0, 0)},
+		{150, 50, NewVector(-1, 0, 0)},
+		{0, 50, NewVector(0, 0, 1)},
+		{100, 0, NewVector(0, 1, 0)},
+		{100, 100, NewVector(0, -1, 0)},
+		{100, 25, NewVector(0, math.Sqrt2/2, -math.Sqrt2/2)},
+	}
+
+	for _, test := range tests {
+		ray, covered := camera.imageRay(test.x, test.y, 0, 0)
+
+		assert.True(t, covered)
+		EqualTuple(t, test.direction, ray.Direction)
+	}
+}
+
+func TestRenderLeavesUncoveredPixelsBlack(t *testing.T) {
+	world := World{}
+	world.Lights = []Light{NewPointLight(NewPoint(0, 0, 0), White)}
+	sky := NewSphere()
+	sky.Transform = sky.Transform.Scale(10, 10, 10)
+	sky.Material.Ambient = 1
+	world.Objects = []Shape{sky}
+
+	camera := NewCamera(20, 10, math.Pi)
+	camera.Projection = FisheyeProjection{}
+	camera.Workers = 1
+	image := camera.Render(world)
+
+	EqualColor(t, Black, image.Pixel[0][0])
+	EqualColor(t, Black, image.Pixel[19][9])
+	assert.NotEqual(t, Black, image.Pixel[10][5])
+}
@@ -0,0 +1,94 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	"math"
+)
+
+type SampleMode int
+
+const (
+	// samples at the centers of a grid of cells within the pixel
+	SampleStratified SampleMode = iota
+	// samples at a random point within each cell of the grid
+	SampleJittered
+)
+
+// offset within pixel (x, y) of sample i, from 0 to 1 in both directions
+func (camera *Camera) SampleOffset(x uint, y uint, i uint) (float64, float64) {
+	// smallest grid with at least one cell per sample, filled row by row
+	columns := uint(math.Ceil(math.Sqrt(float64(camera.Samples))))
+	rows := (camera.Samples + columns - 1) / columns
+
+	jx, jy := 0.5, 0.5
+	if camera.SampleMode == SampleJittered {
+		hash := camera.sampleHash(x, y, i, 0)
+		jx, jy = unitFloat(hash), unitFloat(mix64(hash))
+	}
+
+	return (float64(i%columns) + jx) / float64(columns), (float64(i/columns) + jy) / float64(rows)
+}
+
+// hash of sample i of pixel (x, y) and camera.Seed, salt picks independent
+// streams for different uses
+func (camera *Camera) sampleHash(x uint, y uint, i uint, salt uint64) uint64 {
+	sample := (uint64(x)<<32 | uint64(y)) ^ (uint64(i)+1)*0x9e3779b97f4a7c15
+
+	return mix64(camera.Seed ^ mix64(sample^salt))
+}
+
+// deterministic stream of numbers from 0 up to 1, so the random choices
+// made for a sample are the same whichever worker traces it
+type Sampler struct {
+	state uint64
+}
+
+func NewSampler(seed uint64) *Sampler {
+	return &Sampler{seed}
+}
+
+// next number of the splitmix64 sequence
+func (sampler *Sampler) Float() float64 {
+	sampler.state += 0x9e3779b97f4a7c15
+
+	return unitFloat(mix64(sampler.state))
+}
+
+// direction in the hemisphere around normal for u and v from 0 to 1, more
+// likely the closer it is to normal in proportion to the cosine of the
+// angle between them
+func cosineHemisphere(normal Tuple, u float64, v float64) Tuple {
+	x, y := concentricDisk(u, v)
+	z := math.Sqrt(math.Max(0, 1-x*x-y*y))
+	tangent, bitangent := orthonormalBasis(normal)
+
+	return tangent.Multiply(x).Add(bitangent.Multiply(y)).Add(normal.Multiply(z))
+}
+
+// two unit vectors perpendicular to unit vector normal and to each other
+func orthonormalBasis(normal Tuple) (Tuple, Tuple) {
+	helper := NewVector(1, 0, 0)
+	if math.Abs(normal.X) > 0.9 {
+		helper = NewVector(0, 1, 0)
+	}
+
+	tangent := helper.Cross(normal).Normalize()
+
+	return tangent, normal.Cross(tangent)
+}
+
+// splitmix64 finalizer, spreads similar inputs over all 64 bits
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
+
+// float from 0 up to but not including 1, from the top 53 bits of x
+func unitFloat(x uint64) float64 {
+	return float64(x>>11) / (1 << 53)
+}
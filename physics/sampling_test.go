@@ -0,0 +1,171 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func samplingCamera() Camera {
+	camera := NewCamera(21, 21, math.Pi/2)
+	camera.Transform = ViewTransform(NewPoint(0, 0, -5), NewPoint(0, 0, 0), NewVector(0, 1, 0))
+
+	return camera
+}
+
+func TestStratifiedSampleOffsets(t *testing.T) {
+	camera := NewCamera(10, 10, math.Pi/2)
+	camera.Samples = 4
+
+	expected := [][2]float64{{0.25, 0.25}, {0.75, 0.25}, {0.25, 0.75}, {0.75, 0.75}}
+	for i, offset := range expected {
+		dx, dy := camera.SampleOffset(3, 7, uint(i))
+
+		assert.Equal(t, offset, [2]float64{dx, dy})
+	}
+}
+
+func TestStratifiedSampleOffsetsPartialGrid(t *testing.T) {
+	camera := NewCamera(10, 10, math.Pi/2)
+	camera.Samples = 3
+
+	dx, dy := camera.SampleOffset(0, 0, 2)
+
+	assert.Equal(t, 0.25, dx)
+	assert.Equal(t, 0.75, dy)
+}
+
+func TestJitteredSampleOffsetsStayInCells(t *testing.T) {
+	camera := NewCamera(10, 10, math.Pi/2)
+	camera.Samples = 9
+	camera.SampleMode = SampleJittered
+
+	for i := uint(0); i < 9; i++ {
+		dx, dy := camera.SampleOffset(4, 2, i)
+
+		column, row := float64(i%3), float64(i/3)
+		assert.True(t, dx >= column/3 && dx < (column+1)/3, "dx %v of sample %d", dx, i)
+		assert.True(t, dy >= row/3 && dy < (row+1)/3, "dy %v of sample %d", dy, i)
+	}
+}
+
+func TestJitteredSampleOffsetsDependOnSeed(t *testing.T) {
+	camera := NewCamera(10, 10, math.Pi/2)
+	camera.Samples = 4
+	camera.SampleMode = SampleJittered
+
+	dx1, dy1 := camera.SampleOffset(1, 1, 0)
+	dx2, dy2 := camera.SampleOffset(1, 1, 0)
+	dx3, _ := camera.SampleOffset(2, 1, 0)
+
+	assert.Equal(t, dx1, dx2)
+	assert.Equal(t, dy1, dy2)
+	assert.NotEqual(t, dx1, dx3)
+
+	camera.Seed = 42
+	dx4, _ := camera.SampleOffset(1, 1, 0)
+
+	assert.NotEqual(t, dx1, dx4)
+}
+
+func TestJitteredRenderIsDeterministic(t *testing.T) {
+	world := DefaultWorld()
+	camera := samplingCamera()
+	camera.Samples = 4
+	camera.SampleMode = SampleJittered
+	camera.Seed = 7
+
+	camera.Workers = 1
+	first := camera.Render(world)
+	camera.Workers = 4
+	second := camera.Render(world)
+
+	assert.Equal(t, first.ToPPM(), second.ToPPM())
+
+	camera.Seed = 8
+	third := camera.Render(world)
+
+	assert.NotEqual(t, first.ToPPM(), third.ToPPM())
+}
+
+func TestAdaptiveRenderOnlySamplesEdges(t *testing.T) {
+	world := DefaultWorld()
+	camera := samplingCamera()
+	camera.Samples = 16
+	camera.AdaptiveThreshold = 0.1
+
+	image := camera.Render(world)
+
+	// the background far from the sphere keeps its single sample
+	EqualColor(t, world.ColorAt(camera.RayForPixel(0, 0), 4), image.Pixel[0][0])
+
+	// the silhouette of the sphere is supersampled
+	edge := uint(0)
+	for x := uint(0); x < 21; x++ {
+		center := world.ColorAt(camera.RayForPixel(x, 10), 4)
+		if !center.Equals(Black) {
+			edge = x
+			break
+		}
+	}
+
+	assert.Equal(t, camera.pixelColor(world, edge, 10), image.Pixel[edge][10])
+	assert.NotEqual(t, world.ColorAt(camera.RayForPixel(edge, 10), 4), image.Pixel[edge][10])
+}
+
+func TestAdaptiveRenderWithHighThresholdMatchesOneSample(t *testing.T) {
+	world := DefaultWorld()
+	camera := samplingCamera()
+
+	single := camera.Render(world)
+
+	camera.Samples = 16
+	camera.AdaptiveThreshold = 10
+	adaptive := camera.Render(world)
+
+	assert.Equal(t, single.ToPPM(), adaptive.ToPPM())
+}
+
+func TestSamplerIsDeterministic(t *testing.T) {
+	a, b := NewSampler(42), NewSampler(42)
+	other := NewSampler(43)
+
+	for i := 0; i < 100; i++ {
+		value := a.Float()
+
+		assert.Equal(t, value, b.Float())
+		assert.NotEqual(t, value, other.Float())
+		assert.True(t, value >= 0 && value < 1)
+	}
+}
+
+func TestCosineHemisphere(t *testing.T) {
+	normals := []Tuple{NewVector(0, 1, 0), NewVector(1, 0, 0), NewVector(0, 0, -1), NewVector(1, 2, 3).Normalize()}
+	sampler := NewSampler(1)
+
+	for _, normal := range normals {
+		EqualTuple(t, normal, cosineHemisphere(normal, 0.5, 0.5))
+
+		for i := 0; i < 50; i++ {
+			direction := cosineHemisphere(normal, sampler.Float(), sampler.Float())
+
+			assert.InDelta(t, 1, direction.Magnitude(), 1e-9)
+			assert.True(t, direction.Dot(normal) >= 0)
+		}
+	}
+}
+
+func TestCosineHemisphereAverageCosine(t *testing.T) {
+	normal := NewVector(0, 1, 0)
+	sampler := NewSampler(7)
+	total := 0.0
+
+	for i := 0; i < 10000; i++ {
+		total += cosineHemisphere(normal, sampler.Float(), sampler.Float()).Dot(normal)
+	}
+
+	// the mean cosine of a cosine weighted hemisphere is 2/3
+	assert.InDelta(t, 2.0/3, total/10000, 0.01)
+}
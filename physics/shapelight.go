@@ -0,0 +1,108 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"math"
+)
+
+// light given off by an emissive shape, sampled at points spread over its
+// surface, with the emission of the shape's material as its intensity.
+// Points are lit by the side of the surface its normals face
+type ShapeLight struct {
+	shape       SurfaceSampler
+	samples     uint
+	Jitter      bool
+	Attenuation Attenuation
+	// light for the ambient term of materials, none by default as the
+	// emission is often many times brighter than white
+	Ambient Color
+}
+
+// shape must have a surface that can be sampled, such as a sphere, a cube,
+// a triangle or a group with some of them
+func NewShapeLight(shape Shape, samples uint) *ShapeLight {
+	sampler, ok := shape.(SurfaceSampler)
+	if !ok || sampler.Area() <= 0 {
+		panic("precondition - shape lights need a shape with a surface to sample")
+	}
+
+	if samples == 0 {
+		panic("precondition - shape lights need at least one sample")
+	}
+
+	return &ShapeLight{sampler, samples, true, Attenuation{}, Black}
+}
+
+func (light ShapeLight) GetShape() Shape {
+	return light.shape
+}
+
+func (light ShapeLight) GetIntensity() Color {
+	return light.shape.GetMaterial().Emitted()
+}
+
+func (light ShapeLight) GetAmbient() Color {
+	return light.Ambient
+}
+
+func (light ShapeLight) GetAttenuation() Attenuation {
+	return light.Attenuation
+}
+
+// each sample carries the light of its share of the surface, the emission
+// times area·cosθ/(π·r²), the same as a diffuse bounce finds on average.
+// Samples where the surface faces away from point are left out and the
+// rest are scaled up to make up for them, as Lighting averages them
+func (light ShapeLight) Samples(point Tuple) []LightSample {
+	samples := make([]LightSample, 0, light.samples)
+	emitted := light.GetIntensity()
+	area := light.shape.Area()
+
+	for i := uint(0); i < light.samples; i++ {
+		surface, normal := light.PointOn(i, point)
+		distance := point.Subtract(surface).Magnitude()
+		if distance == 0 {
+			continue
+		}
+
+		cos := normal.Dot(point.Subtract(surface)) / distance
+		if cos <= 0 {
+			continue
+		}
+
+		// just off the surface so the shape does not shadow its own light
+		position := surface.Add(normal.Multiply(Epsilon))
+		weight := area * cos / (math.Pi * distance * distance)
+
+		samples = append(samples, sampleFrom(position, point, emitted.MultiplyScalar(weight)))
+	}
+
+	if len(samples) == 0 {
+		// a single dark sample keeps the average defined
+		surface, _ := light.PointOn(0, point)
+		return []LightSample{sampleFrom(surface, point, Black)}
+	}
+
+	share := float64(len(samples)) / float64(light.samples)
+	for i := range samples {
+		samples[i].Intensity = samples[i].Intensity.MultiplyScalar(share)
+	}
+
+	return samples
+}
+
+// point and normal for sample i, in cell i of a grid over the surface's
+// u and v and jittered deterministically by the shaded point
+func (light ShapeLight) PointOn(i uint, point Tuple) (Tuple, Tuple) {
+	columns := uint(math.Ceil(math.Sqrt(float64(light.samples))))
+	rows := (light.samples + columns - 1) / columns
+
+	ju, jv := 0.5, 0.5
+	if light.Jitter {
+		ju = jitter(point, uint64(i)*2)
+		jv = jitter(point, uint64(i)*2+1)
+	}
+
+	return light.shape.SurfacePoint((float64(i%columns)+ju)/float64(columns), (float64(i/columns)+jv)/float64(rows))
+}
@@ -0,0 +1,160 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sphere of radius 0.5 at (0, 3, 0) glowing orange
+func glowingSphere() *Sphere {
+	sphere := NewSphere()
+	sphere.Transform = sphere.Transform.Translate(0, 3, 0).Scale(0.5, 0.5, 0.5)
+	sphere.Material.Emission = NewColor(1, 0.5, 0)
+	sphere.Material.EmissionStrength = 2
+
+	return sphere
+}
+
+func TestNewShapeLight(t *testing.T) {
+	light := NewShapeLight(glowingSphere(), 16)
+
+	assert.Equal(t, NewColor(2, 1, 0), light.GetIntensity())
+	assert.Equal(t, Black, light.GetAmbient())
+
+	// only the part of the sphere facing the point is sampled
+	samples := light.Samples(NewPoint(0, 0, 0))
+	assert.Greater(t, len(samples), 2)
+	assert.Less(t, len(samples), 10)
+
+	// nothing faces a point inside
+	samples = light.Samples(NewPoint(0, 3, 0))
+	assert.Len(t, samples, 1)
+	assert.Equal(t, Black, samples[0].Intensity)
+}
+
+// solid angle of a sphere of radius at distance from its center over pi,
+// the light a white diffuse surface facing a sphere glowing white gets
+func sphereLight(radius float64, distance float64) float64 {
+	return 2 * (1 - math.Sqrt(1-radius*radius/(distance*distance)))
+}
+
+func TestShapeLightNeedsSurface(t *testing.T) {
+	assert.Panics(t, func() { NewShapeLight(NewPlane(), 4) })
+	assert.Panics(t, func() { NewShapeLight(NewGroup(), 4) })
+	assert.Panics(t, func() { NewShapeLight(NewSphere(), 0) })
+}
+
+func TestShapeLightSamplesOnSurface(t *testing.T) {
+	light := NewShapeLight(glowingSphere(), 64)
+	point := NewPoint(2, 0, 1)
+	samples := light.Samples(point)
+	total := Black
+
+	for _, sample := range samples {
+		position := point.Add(sample.Direction.Multiply(sample.Distance))
+
+		assert.InDelta(t, 0.5, position.Subtract(NewPoint(0, 3, 0)).Magnitude(), 1e-4)
+		assert.InDelta(t, sample.Intensity.Red/2, sample.Intensity.Green, 1e-12)
+		total = total.Add(sample.Intensity)
+	}
+
+	// the samples together carry the light of the sphere's solid angle
+	assert.InEpsilon(t, 2*sphereLight(0.5, math.Sqrt(14)), total.Red/float64(len(samples)), 0.05)
+}
+
+func TestShapeLightSamplesAreDeterministic(t *testing.T) {
+	light := NewShapeLight(glowingSphere(), 9)
+	point := NewPoint(2, 0, 1)
+
+	assert.Equal(t, light.Samples(point), light.Samples(point))
+	assert.NotEqual(t, light.Samples(point), light.Samples(NewPoint(2, 0, 1.5)))
+
+	light.Jitter = false
+	first, _ := light.PointOn(4, point)
+	second, _ := light.PointOn(4, NewPoint(2, 0, 1.5))
+	assert.Equal(t, first, second)
+}
+
+func TestShapeLightDoesNotShadowItself(t *testing.T) {
+	sphere := glowingSphere()
+	world := World{}
+	world.Objects = []Shape{sphere}
+	light := NewShapeLight(sphere, 64)
+	world.Lights = []Light{light}
+
+	assert.Equal(t, 1.0, world.LightVisibility(NewPoint(0, 0, 0), light))
+
+	blocker := NewCube()
+	blocker.Transform = blocker.Transform.Translate(0, 1.5, 0).Scale(2, 0.1, 2)
+	world.Objects = append(world.Objects, blocker)
+
+	assert.Equal(t, 0.0, world.LightVisibility(NewPoint(0, 0, 0), light))
+}
+
+func TestShadeHitAddsEmission(t *testing.T) {
+	sphere := glowingSphere()
+	world := World{}
+	world.Objects = []Shape{sphere}
+	ray := NewRay(NewPoint(0, 3, -5), NewVector(0, 0, 1))
+
+	assert.Equal(t, NewColor(2, 1, 0), world.ColorAt(ray, 4))
+}
+
+func TestShapeLightLightsTheFloor(t *testing.T) {
+	sphere := glowingSphere()
+	world := World{}
+	world.Objects = []Shape{sphere, NewPlane()}
+	world.Lights = []Light{NewShapeLight(sphere, 64)}
+	ray := NewRay(NewPoint(0, 1, -1), NewVector(0, -1, 1).Normalize())
+
+	color := world.ColorAt(ray, 4)
+
+	// only the floor's diffuse, the light adds no ambient
+	assert.InEpsilon(t, 0.9*2*sphereLight(0.5, 3), color.Red, 0.05)
+	assert.InDelta(t, color.Red/2, color.Green, 1e-9)
+	assert.Equal(t, 0.0, color.Blue)
+
+	light := NewShapeLight(sphere, 64)
+	light.Ambient = NewColor(0.5, 0.5, 0.5)
+	world.Lights = []Light{light}
+
+	assert.InDelta(t, color.Red+0.1*0.5, world.ColorAt(ray, 4).Red, 1e-9)
+	assert.InDelta(t, color.Blue+0.1*0.5, world.ColorAt(ray, 4).Blue, 1e-9)
+}
+
+func TestShapeLightMatchesBouncedLight(t *testing.T) {
+	sphere := NewSphere()
+	sphere.Transform = sphere.Transform.Translate(0, 2, 0).Scale(0.25, 0.25, 0.25)
+	sphere.Material.Emission = NewColor(1, 0.5, 0)
+	sphere.Material.EmissionStrength = 20
+
+	world := World{}
+	world.Objects = []Shape{sphere, NewPlane()}
+	ray := NewRay(NewPoint(0, 1, -1), NewVector(0, -1, 1).Normalize())
+
+	tracer := NewPathTracer()
+	average := func(count uint64) Color {
+		color := Black
+		for i := uint64(0); i < count; i++ {
+			color = color.Add(tracer.Trace(world, ray, 1, NewSampler(i)))
+		}
+
+		return color.MultiplyScalar(1 / float64(count))
+	}
+
+	// found only by bouncing off the floor
+	bounced := average(20000)
+	assert.InEpsilon(t, 0.9*20*sphereLight(0.25, 2), bounced.Red, 0.1)
+
+	// sampled directly instead once it is a light
+	world.Lights = []Light{NewShapeLight(sphere, 64)}
+	hit := NewIntersection(math.Sqrt2, world.Objects[1])
+	direct := world.directLight(PrepareComputations(hit, ray, []Intersection{hit}))
+
+	assert.InEpsilon(t, bounced.Red, direct.Red, 0.1)
+	assert.InEpsilon(t, bounced.Red, average(500).Red, 0.1)
+}
@@ -0,0 +1,57 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	"math"
+)
+
+// point light shining a cone along direction, fully lit inside innerAngle and
+// fading smoothly to dark at outerAngle; angles are measured from the axis
+type SpotLight struct {
+	position    Tuple
+	direction   Tuple
+	innerAngle  float64
+	outerAngle  float64
+	intensity   Color
+	Attenuation Attenuation
+}
+
+func NewSpotLight(position Tuple, direction Tuple, innerAngle float64, outerAngle float64, intensity Color) *SpotLight {
+	return &SpotLight{position, direction.Normalize(), innerAngle, outerAngle, intensity, Attenuation{}}
+}
+
+func (light SpotLight) GetIntensity() Color {
+	return light.intensity
+}
+
+func (light SpotLight) GetAmbient() Color {
+	return light.intensity
+}
+
+func (light SpotLight) GetAttenuation() Attenuation {
+	return light.Attenuation
+}
+
+func (light SpotLight) Samples(point Tuple) []LightSample {
+	sample := sampleFrom(light.position, point, light.intensity)
+	sample.Intensity = sample.Intensity.MultiplyScalar(light.Falloff(sample.Direction))
+
+	return []LightSample{sample}
+}
+
+// fraction of the light leaving in the opposite of lightv
+func (light SpotLight) Falloff(lightv Tuple) float64 {
+	cosAngle := lightv.Multiply(-1).Dot(light.direction)
+	cosInner := math.Cos(light.innerAngle)
+	cosOuter := math.Cos(light.outerAngle)
+
+	if cosAngle >= cosInner {
+		return 1
+	} else if cosAngle <= cosOuter {
+		return 0
+	}
+
+	// smoothstep between the two cones
+	x := (cosAngle - cosOuter) / (cosInner - cosOuter)
+	return x * x * (3 - 2*x)
+}
@@ -0,0 +1,55 @@
+package physics
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSpotLight(t *testing.T) {
+	light := NewSpotLight(NewPoint(0, 0, -10), NewVector(0, 0, 2), math.Pi/8, math.Pi/4, White)
+
+	assert.Equal(t, NewPoint(0, 0, -10), light.position)
+	assert.Equal(t, NewVector(0, 0, 1), light.direction)
+	assert.Equal(t, White, light.GetIntensity())
+}
+
+func TestSpotLightFalloff(t *testing.T) {
+	light := NewSpotLight(NewPoint(0, 0, 0), NewVector(0, 0, 1), math.Pi/8, math.Pi/4, White)
+
+	// inside the inner cone
+	assert.Equal(t, 1.0, light.Falloff(NewVector(0, 0, -1)))
+	// outside the outer cone
+	assert.Equal(t, 0.0, light.Falloff(NewVector(0, -math.Sqrt(3)/2, -0.5)))
+	// halfway between the cones
+	angle := 3 * math.Pi / 16
+	cosAngle := math.Cos(angle)
+	x := (cosAngle - math.Cos(math.Pi/4)) / (math.Cos(math.Pi/8) - math.Cos(math.Pi/4))
+	falloff := light.Falloff(NewVector(0, -math.Sin(angle), -cosAngle))
+	assert.InDelta(t, x*x*(3-2*x), falloff, Epsilon)
+	assert.True(t, falloff > 0 && falloff < 1)
+}
+
+func TestLightingWithSpotLight(t *testing.T) {
+	material := NewMaterial()
+	eyev := NewVector(0, 0, -1)
+	normalv := NewVector(0, 0, -1)
+	light := NewSpotLight(NewPoint(0, 0, -10), NewVector(0, 0, 1), math.Pi/16, math.Pi/8, White)
+
+	inside := Lighting(material, NewSphere(), light, NewPoint(0, 0, 0), eyev, normalv, 1.0)
+	outside := Lighting(material, NewSphere(), light, NewPoint(10, 0, 0), eyev, normalv, 1.0)
+
+	EqualColor(t, NewColor(1.9, 1.9, 1.9), inside)
+	EqualColor(t, NewColor(0.1, 0.1, 0.1), outside)
+}
+
+func TestSpotLightShadowStopsAtLight(t *testing.T) {
+	world := DefaultWorld()
+	light := NewSpotLight(NewPoint(-20, 20, -20), NewVector(1, -1, 1), math.Pi/4, math.Pi/3, White)
+
+	assert.Equal(t, 0.0, world.LightVisibility(NewPoint(10, -10, 10), light))
+	assert.Equal(t, 1.0, world.LightVisibility(NewPoint(-30, 30, -30), light))
+}
@@ -8,10 +8,22 @@ import (
 )
 
 type World struct {
-	Light   *PointLight
+	Lights []Light
+	// once a BVH is built, change these through AddObject or SetObjects so
+	// it is known to be out of date
 	Objects []Shape
+	// rays a glossy reflection or a rough refraction is spread over at one
+	// hit, hits further along those rays get a single ray each so the count
+	// does not multiply. 0 uses DefaultGlossySamples
+	GlossySamples uint
+	bvh           *BVH
+	bvhSplit      BVHSplit
+	// objects changed since the BVH was built
+	bvhStale bool
 }
 
+const DefaultGlossySamples = 16
+
 func DefaultWorld() World {
 	s1 := NewSphere()
 	material := s1.GetMaterial()
@@ -23,21 +35,59 @@ func DefaultWorld() World {
 	s2.Transform = s2.Transform.Scale(0.5, 0.5, 0.5)
 
 	world := World{}
-	world.Light = &PointLight{NewPoint(-10, 10, -10), White}
+	world.Lights = []Light{NewPointLight(NewPoint(-10, 10, -10), White)}
 	world.Objects = make([]Shape, 0)
 	world.Objects = append(world.Objects, s1, s2)
 
 	return world
 }
 
+// builds a BVH over the objects and the children of every group
+func (world *World) BuildBVH(split BVHSplit) {
+	for _, object := range world.Objects {
+		if group, ok := object.(*Group); ok {
+			group.BuildBVH(split)
+		}
+	}
+
+	world.bvh = NewBVH(world.Objects, split)
+	world.bvhSplit = split
+	world.bvhStale = false
+}
+
+// objects are tested one by one until the BVH is built again, which
+// Camera.Render does on demand
+func (world *World) AddObject(objects ...Shape) {
+	world.Objects = append(world.Objects, objects...)
+	world.bvhStale = true
+}
+
+// replaces all the objects, like AddObject the BVH is out of date until it
+// is built again
+func (world *World) SetObjects(objects []Shape) {
+	world.Objects = objects
+	world.bvhStale = true
+}
+
+// builds the BVH again when the objects changed since it was built
+func (world *World) refreshBVH() {
+	if world.bvh != nil && world.bvhStale {
+		world.BuildBVH(world.bvhSplit)
+	}
+}
+
 func (world World) Intersect(ray Ray) []Intersection {
 	intersections := make([]Intersection, 0)
 
-	for _, object := range world.Objects {
-		temp := object.Intersects(ray)
+	if world.bvh != nil && !world.bvhStale {
+		intersections = world.bvh.Intersects(ray)
+	} else {
+		for _, object := range world.Objects {
+			temp := object.Intersects(ray)
 
-		for _, t := range temp {
-			intersections = append(intersections, t)
+			for _, t := range temp {
+				intersections = append(intersections, t)
+			}
 		}
 	}
 
@@ -52,19 +102,35 @@ func (world World) Intersect(ray Ray) []Intersection {
 	return intersections
 }
 
+func (world World) glossyBudget() uint {
+	if world.GlossySamples == 0 {
+		return DefaultGlossySamples
+	}
+
+	return world.GlossySamples
+}
+
 func (world World) ShadeHit(comps Comps, remaining uint) Color {
-	shadowed := world.IsShadowed(comps.overPoint)
+	return world.shadeHit(comps, remaining, world.glossyBudget())
+}
 
-	surface := Lighting(
-		comps.object.GetMaterial(),
-		comps.object,
-		*world.Light,
-		comps.point, comps.eyev, comps.normalv, shadowed)
+func (world World) shadeHit(comps Comps, remaining uint, budget uint) Color {
+	surface := comps.object.GetMaterial().Emitted()
 
-	reflected := world.ReflectedColor(comps, remaining)
-	refracted := world.RefractedColor(comps, remaining)
+	for _, light := range world.Lights {
+		surface = surface.Add(world.lightAt(comps.object.GetMaterial(), comps, light))
+	}
+
+	refracted := world.refractedColor(comps, remaining, budget)
 
 	material := comps.object.GetMaterial()
+	if material.PBR != nil {
+		// the Fresnel term of the PBR material replaces the Schlick blend
+		return surface.Add(world.glossyColor(comps, remaining, budget)).Add(refracted)
+	}
+
+	reflected := world.reflectedColor(comps, remaining, budget)
+
 	if material.Reflective > 0 && material.Transparency > 0 {
 		reflectance := comps.Schlick()
 		return surface.Add(
@@ -76,6 +142,10 @@ func (world World) ShadeHit(comps Comps, remaining uint) Color {
 }
 
 func (world World) ColorAt(ray Ray, remaining uint) Color {
+	return world.colorAt(ray, remaining, world.glossyBudget())
+}
+
+func (world World) colorAt(ray Ray, remaining uint, budget uint) Color {
 	intersections := world.Intersect(ray)
 	hit, err := Hit(intersections)
 
@@ -84,19 +154,55 @@ func (world World) ColorAt(ray Ray, remaining uint) Color {
 	}
 
 	comps := PrepareComputations(hit, ray, intersections)
-	return world.ShadeHit(comps, remaining)
+	return world.shadeHit(comps, remaining, budget).Multiply(comps.Transmittance())
+}
+
+// light from one light shading the hit, its samples are taken once and
+// used both to shade and to test for shadows
+func (world World) lightAt(material Material, comps Comps, light Light) Color {
+	samples := light.Samples(comps.point)
+	visibility := world.visibility(comps.overPoint, comps.point, samples)
+
+	return lightingSamples(material, comps.object, light, samples, comps.point, comps.eyev, comps.normalv, visibility)
+}
+
+// returns the fraction of the light's samples visible from point,
+// 1 when fully lit and 0 when fully in shadow
+func (world World) LightVisibility(point Tuple, light Light) float64 {
+	return world.visibility(point, point, light.Samples(point))
+}
+
+// fraction of samples taken for point that are visible from origin, a
+// point just off the surface near it
+func (world World) visibility(origin Tuple, point Tuple, samples []LightSample) float64 {
+	visible := 0
+
+	for _, sample := range samples {
+		if !world.isOccluded(origin, seenFrom(sample, point, origin)) {
+			visible++
+		}
+	}
+
+	return float64(visible) / float64(len(samples))
+}
+
+// a sample taken for point as seen from origin instead, samples of lights
+// at infinity look the same from anywhere
+func seenFrom(sample LightSample, point Tuple, origin Tuple) LightSample {
+	if math.IsInf(sample.Distance, 1) {
+		return sample
+	}
+
+	return sampleFrom(point.Add(sample.Direction.Multiply(sample.Distance)), origin, sample.Intensity)
 }
 
-func (world World) IsShadowed(point Tuple) bool {
-	vector := world.Light.position.Subtract(point)
-	distance := vector.Magnitude()
-	direction := vector.Normalize()
-	ray := NewRay(point, direction)
+func (world World) isOccluded(point Tuple, sample LightSample) bool {
+	ray := NewRay(point, sample.Direction)
 
 	intersection := world.Intersect(ray)
 	hit, err := Hit(intersection)
 
-	if err == nil && hit.T < distance {
+	if err == nil && hit.T < sample.Distance {
 		return true
 	}
 
@@ -104,34 +210,115 @@ func (world World) IsShadowed(point Tuple) bool {
 }
 
 func (world World) ReflectedColor(comps Comps, remaining uint) Color {
-	if remaining == 0 || comps.object.GetMaterial().Reflective == 0 {
+	return world.reflectedColor(comps, remaining, world.glossyBudget())
+}
+
+func (world World) reflectedColor(comps Comps, remaining uint, budget uint) Color {
+	material := comps.object.GetMaterial()
+	if remaining == 0 || material.Reflective == 0 {
 		return Black
 	}
 
-	reflectRay := NewRay(comps.overPoint, comps.reflectv)
-	color := world.ColorAt(reflectRay, remaining-1)
+	if material.Roughness == 0 {
+		reflectRay := NewRay(comps.overPoint, comps.reflectv)
+		color := world.colorAt(reflectRay, remaining-1, budget)
+
+		return color.MultiplyScalar(material.Reflective)
+	}
+
+	color := world.spreadRays(comps, remaining, budget, 0, func(u float64, v float64) (Ray, Color) {
+		direction, reflects := roughReflection(comps.normalv, comps.eyev, material.Roughness, u, v)
+		if !reflects {
+			return Ray{}, Black
+		}
+
+		return NewRay(comps.overPoint, direction), White
+	})
 
-	return color.MultiplyScalar(comps.object.GetMaterial().Reflective)
+	return color.MultiplyScalar(material.Reflective)
 }
 
 func (world World) RefractedColor(comps Comps, remaining uint) Color {
-	if remaining == 0 || comps.object.GetMaterial().Transparency == 0 {
+	return world.refractedColor(comps, remaining, world.glossyBudget())
+}
+
+func (world World) refractedColor(comps Comps, remaining uint, budget uint) Color {
+	material := comps.object.GetMaterial()
+	if remaining == 0 || material.Transparency == 0 {
 		return Black
 	}
 
+	if material.Roughness == 0 {
+		direction, refracts := comps.RefractedDirection()
+		if !refracts {
+			// total internal reflection
+			return Black
+		}
+
+		refractRay := NewRay(comps.underPoint, direction)
+		color := world.colorAt(refractRay, remaining-1, budget)
+
+		return color.MultiplyScalar(material.Transparency)
+	}
+
 	nRatio := comps.n1 / comps.n2
-	cosI := comps.eyev.Dot(comps.normalv)
-	sin2T := math.Pow(nRatio, 2) * (1 - math.Pow(cosI, 2))
+	color := world.spreadRays(comps, remaining, budget, 1<<32, func(u float64, v float64) (Ray, Color) {
+		direction, refracts := roughRefraction(comps.normalv, comps.eyev, nRatio, material.Roughness, u, v)
+		if !refracts {
+			return Ray{}, Black
+		}
+
+		return NewRay(comps.underPoint, direction), White
+	})
+
+	return color.MultiplyScalar(material.Transparency)
+}
 
-	if sin2T > 1 {
-		// total internal reflection
+// light reflected off a PBR material, weighted by its Fresnel term and
+// spread over its GGX lobe with up to budget rays
+func (world World) glossyColor(comps Comps, remaining uint, budget uint) Color {
+	if remaining == 0 {
 		return Black
 	}
 
-	cosT := math.Sqrt(1.0 - sin2T)
-	direction := comps.normalv.Multiply(nRatio*cosI - cosT).Subtract(comps.eyev.Multiply(nRatio))
-	refractRay := NewRay(comps.underPoint, direction)
-	color := world.ColorAt(refractRay, remaining-1).MultiplyScalar(comps.object.GetMaterial().Transparency)
+	sample := comps.object.GetMaterial().PBR.At(comps.object, comps.point)
+
+	if sample.Roughness == 0 {
+		reflectRay := NewRay(comps.overPoint, comps.reflectv)
+		fresnel := fresnelSchlick(sample.F0(), comps.eyev.Dot(comps.normalv))
+
+		return world.colorAt(reflectRay, remaining-1, budget).Multiply(fresnel)
+	}
+
+	return world.spreadRays(comps, remaining, budget, 0, func(u float64, v float64) (Ray, Color) {
+		direction, weight := ggxReflection(sample, comps.normalv, comps.eyev, u, v)
+
+		return NewRay(comps.overPoint, direction), weight
+	})
+}
+
+// average of the light along budget rays spread over a lobe, ray picks
+// each one and its weight for u and v from 0 to 1, with a black weight
+// for rays that are lost. The numbers are jittered by the hit and salt so
+// renders repeat
+func (world World) spreadRays(comps Comps, remaining uint, budget uint, salt uint64, ray func(u float64, v float64) (Ray, Color)) Color {
+	count := budget
+	if count < 1 {
+		count = 1
+	}
+
+	color := Black
+	for i := uint(0); i < count; i++ {
+		u := (float64(i) + jitter(comps.point, salt+uint64(i)*2)) / float64(count)
+		v := jitter(comps.point, salt+uint64(i)*2+1)
+
+		sample, weight := ray(u, v)
+		if weight == Black {
+			continue
+		}
+
+		color = color.Add(world.colorAt(sample, remaining-1, 1).Multiply(weight))
+	}
 
-	return color
+	return color.MultiplyScalar(1 / float64(count))
 }
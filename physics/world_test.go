@@ -12,12 +12,12 @@ import (
 func TestNewWorld(t *testing.T) {
 	world := World{}
 
-	assert.Nil(t, world.Light)
+	assert.Nil(t, world.Lights)
 	assert.Nil(t, world.Objects)
 }
 
 func TestDefaultWorld(t *testing.T) {
-	light := &PointLight{NewPoint(-10, 10, -10), White}
+	lights := []Light{NewPointLight(NewPoint(-10, 10, -10), White)}
 	s1 := NewSphere()
 	s1.Material.Color = NewColor(0.8, 1.0, 0.6)
 	s1.Material.Diffuse = 0.7
@@ -27,7 +27,7 @@ func TestDefaultWorld(t *testing.T) {
 
 	world := DefaultWorld()
 
-	assert.Equal(t, light, world.Light)
+	assert.Equal(t, lights, world.Lights)
 	assert.Equal(t, s1, world.Objects[0])
 	assert.Equal(t, s2, world.Objects[1])
 }
@@ -84,7 +84,7 @@ func TestShadeHitIntersectionInShadow(t *testing.T) {
 	s2.Transform = s2.Transform.Translate(0, 0, 10)
 
 	world := World{}
-	world.Light = &PointLight{NewPoint(0, 0, -10), White}
+	world.Lights = []Light{NewPointLight(NewPoint(0, 0, -10), White)}
 	world.Objects = make([]Shape, 0)
 	world.Objects = append(world.Objects, s1, s2)
 
@@ -99,28 +99,28 @@ func TestNoShadowWhenNothingCollinearWithPointAndLight(t *testing.T) {
 	world := DefaultWorld()
 	point := NewPoint(0, 10, 0)
 
-	assert.Equal(t, false, world.IsShadowed(point))
+	assert.Equal(t, 1.0, world.LightVisibility(point, world.Lights[0]))
 }
 
 func TestShadowWhenObjectBetweenPointAndLight(t *testing.T) {
 	world := DefaultWorld()
 	point := NewPoint(10, -10, 10)
 
-	assert.Equal(t, true, world.IsShadowed(point))
+	assert.Equal(t, 0.0, world.LightVisibility(point, world.Lights[0]))
 }
 
 func TestNoShadowWhenObjectBehindLight(t *testing.T) {
 	world := DefaultWorld()
 	point := NewPoint(-20, 20, -20)
 
-	assert.Equal(t, false, world.IsShadowed(point))
+	assert.Equal(t, 1.0, world.LightVisibility(point, world.Lights[0]))
 }
 
 func TestNoShadowWhenObjectBehindPoint(t *testing.T) {
 	world := DefaultWorld()
 	point := NewPoint(-2, 2, -2)
 
-	assert.Equal(t, false, world.IsShadowed(point))
+	assert.Equal(t, 1.0, world.LightVisibility(point, world.Lights[0]))
 }
 
 func TestShadingIntersection(t *testing.T) {
@@ -137,7 +137,7 @@ func TestShadingIntersection(t *testing.T) {
 
 func TestShadingIntersectionFromInside(t *testing.T) {
 	world := DefaultWorld()
-	world.Light = &PointLight{NewPoint(0, 0.25, 0), White}
+	world.Lights = []Light{NewPointLight(NewPoint(0, 0.25, 0), White)}
 	ray := NewRay(NewPoint(0, 0, 0), NewVector(0, 0, 1))
 	shape := world.Objects[1]
 	intersection := NewIntersection(0.5, shape)
@@ -192,7 +192,7 @@ func TestShadeHitOfReflectiveMaterial(t *testing.T) {
 
 func TestColorAtMutuallyReflectiveSurfaces(t *testing.T) {
 	world := World{}
-	world.Light = &PointLight{NewPoint(0, 0, 0), NewColor(1, 1, 1)}
+	world.Lights = []Light{NewPointLight(NewPoint(0, 0, 0), NewColor(1, 1, 1))}
 
 	lower := NewPlane()
 	lower.Material.Reflective = 1
@@ -346,3 +346,293 @@ func TestShadeHitWithReflectiveTransparentMaterial(t *testing.T) {
 
 	EqualColor(t, NewColor(0.93391, 0.69643, 0.69243), color)
 }
+
+func TestShadeHitSumsEachLight(t *testing.T) {
+	world := DefaultWorld()
+	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+	shape := world.Objects[0]
+	intersection := NewIntersection(4, shape)
+	comps := PrepareComputations(intersection, ray, []Intersection{})
+	single := world.ShadeHit(comps, 4)
+
+	world.Lights = append(world.Lights, NewPointLight(NewPoint(-10, 10, -10), White))
+	color := world.ShadeHit(comps, 4)
+
+	EqualColor(t, single.MultiplyScalar(2), color)
+}
+
+func TestShadeHitShadowTestedPerLight(t *testing.T) {
+	s1 := NewSphere()
+	s2 := NewSphere()
+	s2.Transform = s2.Transform.Translate(0, 0, 10)
+
+	world := World{}
+	world.Lights = []Light{
+		NewPointLight(NewPoint(0, 0, -10), White),
+		NewPointLight(NewPoint(0, 0, 5), White)}
+	world.Objects = append(world.Objects, s1, s2)
+
+	ray := NewRay(NewPoint(0, 0, 5), NewVector(0, 0, 1))
+	intersection := NewIntersection(4, s2)
+	comps := PrepareComputations(intersection, ray, []Intersection{})
+
+	// the first light is blocked by s1, the second sits between the eye and s2
+	EqualColor(t, NewColor(2, 2, 2), world.ShadeHit(comps, 4))
+	assert.Equal(t, 0.0, world.LightVisibility(comps.overPoint, world.Lights[0]))
+	assert.Equal(t, 1.0, world.LightVisibility(comps.overPoint, world.Lights[1]))
+}
+
+func TestShadowCastByCone(t *testing.T) {
+	world := World{}
+	cone := NewCone()
+	cone.Minimum = -1
+	cone.Maximum = 0
+	cone.Closed = true
+	cone.Transform = cone.Transform.Translate(0, 0.5, 5)
+	world.Objects = append(world.Objects, cone)
+	light := NewPointLight(NewPoint(0, 0, 0), White)
+
+	assert.Equal(t, 0.0, world.LightVisibility(NewPoint(0, 0, 10), light))
+	assert.Equal(t, 1.0, world.LightVisibility(NewPoint(0, 10, 10), light))
+}
+
+func TestBVHRenderMatchesBruteForce(t *testing.T) {
+	world := DefaultWorld()
+	floor := NewPlane()
+	floor.Transform = floor.Transform.Translate(0, -1, 0)
+	floor.Material.Reflective = 0.5
+	group := NewGroup()
+	for i := 0; i < 10; i++ {
+		cube := NewCube()
+		cube.Transform = cube.Transform.Translate(float64(i)-5, 0, 3).Scale(0.3, 0.3, 0.3)
+		group.AddChild(cube)
+	}
+	world.Objects = append(world.Objects, floor, group)
+
+	camera := NewCamera(30, 20, math.Pi/2)
+	camera.Transform = ViewTransform(NewPoint(0, 1.5, -5), NewPoint(0, 0, 0), NewVector(0, 1, 0))
+
+	expected := camera.Render(world)
+	world.BuildBVH(SplitSAH)
+	actual := camera.Render(world)
+
+	assert.NotNil(t, world.bvh)
+	assert.Equal(t, expected.ToPPM(), actual.ToPPM())
+}
+
+func TestObjectsChangedAfterBuildingBVH(t *testing.T) {
+	world := DefaultWorld()
+	world.BuildBVH(SplitSAH)
+
+	sphere := NewSphere()
+	sphere.Transform = sphere.Transform.Translate(0, 0, 5)
+	world.AddObject(sphere)
+
+	ray := NewRay(NewPoint(0, 0, 10), NewVector(0, 0, -1))
+	xs := world.Intersect(ray)
+
+	assert.Len(t, xs, 6)
+	assert.Equal(t, sphere, xs[0].Object)
+	assert.Equal(t, 4.0, xs[0].T)
+
+	// as many objects removed as added
+	world.BuildBVH(SplitSAH)
+	other := NewSphere()
+	other.Transform = other.Transform.Translate(0, 0, -5)
+	world.SetObjects([]Shape{world.Objects[1], other, world.Objects[2]})
+
+	xs = world.Intersect(ray)
+	assert.Len(t, xs, 6)
+	assert.Equal(t, 4.0, xs[0].T)
+	assert.Equal(t, 16.0, xs[5].T)
+}
+
+func TestOutOfDateBVHIsRebuiltOnDemand(t *testing.T) {
+	world := DefaultWorld()
+	world.BuildBVH(SplitSAH)
+	world.SetObjects(world.Objects[:1])
+
+	world.refreshBVH()
+
+	assert.False(t, world.bvhStale)
+	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+	assert.Len(t, world.Intersect(ray), 2)
+}
+
+func TestGlossyReflectionBlurs(t *testing.T) {
+	// enough rays for the rough reflection to settle
+	world := World{GlossySamples: 1024}
+	world.Lights = []Light{NewPointLight(NewPoint(0, 10, 0), White)}
+	floor := NewPlane()
+	floor.Material.Ambient = 0
+	floor.Material.Diffuse = 0
+	floor.Material.Specular = 0
+	floor.Material.Reflective = 1
+	ceiling := NewPlane()
+	ceiling.Transform = ceiling.Transform.Translate(0, 3, 0)
+	ceiling.Material.Ambient = 1
+	world.Objects = []Shape{floor, ceiling}
+	ray := NewRay(NewPoint(1.5, 1, -0.5), NewVector(0, -1, 1).Normalize())
+
+	// rays spread below the floor are lost
+	floor.Material.Roughness = 0.6
+	plain := world.ColorAt(ray, 4)
+	assert.InDelta(t, 0.82, plain.Red, 0.02)
+
+	// the mirror sees a white square at (1.5, 3, 3.5), the rough floor
+	// averages it with the black squares around it
+	ceiling.Material.Pattern = NewCheckersPattern(NewSolidPattern(White), NewSolidPattern(Black))
+	floor.Material.Roughness = 0
+	assert.Equal(t, White, world.ColorAt(ray, 4))
+
+	floor.Material.Roughness = 0.6
+	assert.InDelta(t, plain.Red/2, world.ColorAt(ray, 4).Red, 0.02)
+}
+
+func TestGlossySamplesBudget(t *testing.T) {
+	world := World{GlossySamples: 1}
+	world.Lights = []Light{NewPointLight(NewPoint(0, 10, 0), White)}
+	floor := NewPlane()
+	floor.Material.Reflective = 0.8
+	floor.Material.Roughness = 0.6
+	ceiling := NewPlane()
+	ceiling.Transform = ceiling.Transform.Translate(0, 3, 0)
+	ceiling.Material.Pattern = NewCheckersPattern(NewSolidPattern(White), NewSolidPattern(Black))
+	ceiling.Material.Ambient = 1
+	world.Objects = []Shape{floor, ceiling}
+	ray := NewRay(NewPoint(1.5, 1, -0.5), NewVector(0, -1, 1).Normalize())
+
+	xs := world.Intersect(ray)
+	hit, _ := Hit(xs)
+	comps := PrepareComputations(hit, ray, xs)
+
+	// a single ray picked by the jitter of the hit
+	direction, reflects := roughReflection(comps.normalv, comps.eyev, 0.6, jitter(comps.point, 0), jitter(comps.point, 1))
+	assert.True(t, reflects)
+
+	expected := world.ColorAt(NewRay(comps.overPoint, direction), 3).MultiplyScalar(0.8)
+	EqualColor(t, expected, world.ReflectedColor(comps, 4))
+	EqualColor(t, Black, world.ReflectedColor(comps, 0))
+
+	world.GlossySamples = 0
+	spread := world.ReflectedColor(comps, 4)
+	world.GlossySamples = DefaultGlossySamples
+	assert.Equal(t, spread, world.ReflectedColor(comps, 4))
+}
+
+func TestFrostedRefractionBlurs(t *testing.T) {
+	world := World{GlossySamples: 1024}
+	world.Lights = []Light{NewPointLight(NewPoint(0, 10, 0), White)}
+	floor := NewPlane()
+	floor.Material.Ambient = 0
+	floor.Material.Diffuse = 0
+	floor.Material.Specular = 0
+	floor.Material.Transparency = 1
+	floor.Material.RefractiveIndex = 1.5
+	below := NewPlane()
+	below.Transform = below.Transform.Translate(0, -10, 0)
+	below.Material.Ambient = 1
+	world.Objects = []Shape{floor, below}
+	ray := NewRay(NewPoint(1.5, 1, -0.5), NewVector(0, -1, 1).Normalize())
+
+	// a few rays are totally reflected inside the floor
+	floor.Material.Roughness = 0.6
+	plain := world.ColorAt(ray, 4)
+	assert.InDelta(t, 0.97, plain.Red, 0.02)
+
+	below.Material.Pattern = NewCheckersPattern(NewSolidPattern(White), NewSolidPattern(Black))
+	floor.Material.Roughness = 0
+	assert.Equal(t, Black, world.ColorAt(ray, 4))
+
+	floor.Material.Roughness = 0.6
+	assert.InDelta(t, plain.Red/2, world.ColorAt(ray, 4).Red, 0.02)
+}
+
+func TestShadeHitBlendsRoughReflectionAndRefraction(t *testing.T) {
+	world := World{}
+	world.Lights = []Light{NewPointLight(NewPoint(0, 10, 0), White)}
+	floor := NewPlane()
+	floor.Material.Ambient = 0
+	floor.Material.Diffuse = 0
+	floor.Material.Specular = 0
+	floor.Material.Reflective = 0.5
+	floor.Material.Transparency = 0.5
+	floor.Material.RefractiveIndex = 1.5
+	floor.Material.Roughness = 0.4
+	checkers := NewCheckersPattern(NewSolidPattern(White), NewSolidPattern(Black))
+	ceiling := NewPlane()
+	ceiling.Transform = ceiling.Transform.Translate(0, 3, 0)
+	ceiling.Material.Pattern = checkers
+	ceiling.Material.Ambient = 1
+	below := NewPlane()
+	below.Transform = below.Transform.Translate(0, -10, 0)
+	below.Material.Pattern = checkers
+	below.Material.Ambient = 1
+	world.Objects = []Shape{floor, ceiling, below}
+	ray := NewRay(NewPoint(1.5, 1, -0.5), NewVector(0, -1, 1).Normalize())
+
+	xs := world.Intersect(ray)
+	hit, _ := Hit(xs)
+	comps := PrepareComputations(hit, ray, xs)
+
+	reflectance := comps.Schlick()
+	expected := world.ReflectedColor(comps, 4).MultiplyScalar(reflectance).Add(
+		world.RefractedColor(comps, 4).MultiplyScalar(1 - reflectance))
+
+	EqualColor(t, expected, world.ShadeHit(comps, 4))
+}
+
+func TestAbsorptionGrowsWithThickness(t *testing.T) {
+	world := World{}
+	slab := NewCube()
+	slab.Material.Ambient = 0
+	slab.Material.Diffuse = 0
+	slab.Material.Specular = 0
+	slab.Material.Transparency = 1
+	slab.Material.RefractiveIndex = 1.5
+	// a glowing backdrop behind the slab
+	backdrop := NewPlane()
+	backdrop.Transform = backdrop.Transform.Translate(0, 0, 5).RotateX(math.Pi / 2)
+	backdrop.Material.Ambient = 0
+	backdrop.Material.Diffuse = 0
+	backdrop.Material.Specular = 0
+	backdrop.Material.Emission = White
+	world.Objects = []Shape{slab, backdrop}
+	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+
+	assert.Equal(t, White, world.ColorAt(ray, 5))
+
+	slab.Material.Absorption = NewColor(0.5, 0.2, 0)
+	for _, thickness := range []float64{0.5, 1, 2} {
+		slab.SetTransform(NewIdentityMatrix().Scale(1, 1, thickness/2))
+		expected := NewColor(math.Exp(-0.5*thickness), math.Exp(-0.2*thickness), 1)
+
+		color := world.ColorAt(ray, 5)
+		assert.InDelta(t, expected.Red, color.Red, 1e-4, thickness)
+		assert.InDelta(t, expected.Green, color.Green, 1e-4, thickness)
+		assert.Equal(t, 1.0, color.Blue)
+	}
+}
+
+func TestPathTracerAbsorption(t *testing.T) {
+	world := World{}
+	slab := NewCube()
+	slab.Material.Diffuse = 0
+	slab.Material.Specular = 0
+	slab.Material.Transparency = 1
+	slab.Material.RefractiveIndex = 1.5
+	slab.Material.Absorption = NewColor(0.5, 0.2, 0)
+	backdrop := NewPlane()
+	backdrop.Transform = backdrop.Transform.Translate(0, 0, 5).RotateX(math.Pi / 2)
+	backdrop.Material.Diffuse = 0
+	backdrop.Material.Specular = 0
+	backdrop.Material.Emission = White
+	world.Objects = []Shape{slab, backdrop}
+	ray := NewRay(NewPoint(0, 0, -5), NewVector(0, 0, 1))
+
+	// two units of glass
+	color := NewPathTracer().Trace(world, ray, 5, NewSampler(1))
+
+	assert.InDelta(t, math.Exp(-1), color.Red, 1e-4)
+	assert.InDelta(t, math.Exp(-0.4), color.Green, 1e-4)
+}
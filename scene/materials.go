@@ -0,0 +1,474 @@
+package scene
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"go-raytracer/image"
+
+	"gopkg.in/yaml.v3"
+)
+
+func (loader *loader) material(node *yaml.Node) (Material, error) {
+	material := NewMaterial()
+
+	node, err := loader.resolve(node)
+	if err != nil {
+		return material, err
+	}
+
+	fields, err := loader.fields(node, "material")
+	if err != nil {
+		return material, err
+	}
+
+	for _, field := range fields {
+		switch field.key.Value {
+		case "color":
+			material.Color, err = loader.color(field.value)
+		case "pattern":
+			material.Pattern, err = loader.pattern(field.value, 0)
+		case "ambient":
+			material.Ambient, err = loader.float(field.value)
+		case "diffuse":
+			material.Diffuse, err = loader.float(field.value)
+		case "specular":
+			material.Specular, err = loader.float(field.value)
+		case "shininess":
+			material.Shininess, err = loader.float(field.value)
+		case "reflective":
+			material.Reflective, err = loader.float(field.value)
+		case "transparency":
+			material.Transparency, err = loader.float(field.value)
+		case "refractive-index":
+			material.RefractiveIndex, err = loader.float(field.value)
+		case "roughness":
+			material.Roughness, err = loader.fraction(field.value)
+		case "absorption":
+			material.Absorption, err = loader.color(field.value)
+		case "absorption-density":
+			material.AbsorptionDensity, err = loader.float(field.value)
+		case "emission":
+			material.Emission, err = loader.color(field.value)
+		case "emission-strength":
+			material.EmissionStrength, err = loader.float(field.value)
+		case "pbr":
+			material.PBR, err = loader.pbr(field.value)
+		default:
+			err = loader.errorf(field.key, "unknown key %q in material", field.key.Value)
+		}
+
+		if err != nil {
+			return material, err
+		}
+	}
+
+	return material, nil
+}
+
+// metallic/roughness parameters, each with an optional pattern map
+func (loader *loader) pbr(node *yaml.Node) (*PBR, error) {
+	pbr := NewPBR(White, 0, 0.5)
+
+	node, err := loader.resolve(node)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := loader.fields(node, "pbr")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range fields {
+		switch field.key.Value {
+		case "base-color":
+			pbr.BaseColor, err = loader.color(field.value)
+		case "base-color-map":
+			pbr.BaseColorMap, err = loader.pattern(field.value, 0)
+		case "metallic":
+			pbr.Metallic, err = loader.fraction(field.value)
+		case "metallic-map":
+			pbr.MetallicMap, err = loader.pattern(field.value, 0)
+		case "roughness":
+			pbr.Roughness, err = loader.fraction(field.value)
+		case "roughness-map":
+			pbr.RoughnessMap, err = loader.pattern(field.value, 0)
+		case "specular":
+			pbr.Specular, err = loader.fraction(field.value)
+		case "specular-map":
+			pbr.SpecularMap, err = loader.pattern(field.value, 0)
+		default:
+			err = loader.errorf(field.key, "unknown key %q in pbr", field.key.Value)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pbr, nil
+}
+
+// a number from 0 to 1
+func (loader *loader) fraction(node *yaml.Node) (float64, error) {
+	value, err := loader.float(node)
+	if err == nil && (value < 0 || value > 1) {
+		err = loader.errorf(node, "expected a number from 0 to 1, got %s", node.Value)
+	}
+
+	return value, err
+}
+
+// a pattern mapping, a defined name, or a color as shorthand for a solid pattern
+func (loader *loader) pattern(node *yaml.Node, depth int) (Pattern, error) {
+	if depth > maxDefinitionDepth {
+		return nil, loader.errorf(node, "definitions nested too deeply")
+	}
+
+	if node.Kind == yaml.ScalarNode {
+		base, err := loader.lookup(node)
+		if err != nil {
+			return nil, err
+		}
+
+		return loader.pattern(base, depth+1)
+	}
+
+	if node.Kind == yaml.SequenceNode {
+		color, err := loader.color(node)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewSolidPattern(color), nil
+	}
+
+	fields, err := loader.fields(node, "pattern")
+	if err != nil {
+		return nil, err
+	}
+
+	kindNode := findKey(node, "type")
+	if kindNode == nil {
+		return nil, loader.errorf(node, "pattern has no type")
+	}
+
+	transform := NewIdentityMatrix()
+	var color, mapping, uvPattern *yaml.Node
+	var patterns []Pattern
+
+	for _, field := range fields {
+		switch field.key.Value {
+		case "type":
+		case "transform":
+			transform, err = loader.transform(field.value)
+		case "color":
+			color = field.value
+		case "patterns":
+			patterns, err = loader.subpatterns(field.value, depth)
+		case "mapping":
+			mapping = field.value
+		case "uv-pattern":
+			uvPattern = field.value
+		default:
+			err = loader.errorf(field.key, "unknown key %q in pattern", field.key.Value)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var pattern Pattern
+	var impl *PatternImpl
+
+	switch kindNode.Value {
+	case "solid":
+		if color == nil {
+			return nil, loader.errorf(node, "solid pattern needs a color")
+		}
+
+		value, err := loader.color(color)
+		if err != nil {
+			return nil, err
+		}
+
+		solid := NewSolidPattern(value)
+		pattern, impl = solid, &solid.PatternImpl
+	case "test":
+		test := NewTestPattern()
+		pattern, impl = test, &test.PatternImpl
+	case "stripes", "gradient", "rings", "checkers":
+		if len(patterns) != 2 {
+			return nil, loader.errorf(node, "%s pattern needs a list of 2 patterns", kindNode.Value)
+		}
+
+		pattern, impl = newBlendPattern(kindNode.Value, patterns[0], patterns[1])
+	case "map":
+		if mapping == nil || uvPattern == nil {
+			return nil, loader.errorf(node, "map pattern needs a mapping and a uv-pattern")
+		}
+
+		textureMap, err := loader.textureMap(mapping, uvPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		pattern, impl = textureMap, &textureMap.PatternImpl
+	default:
+		return nil, loader.errorf(kindNode, "unknown pattern %q", kindNode.Value)
+	}
+
+	impl.Transform = transform
+
+	return pattern, nil
+}
+
+func (loader *loader) textureMap(mappingNode *yaml.Node, uvPatternNode *yaml.Node) (*TextureMapPattern, error) {
+	mappings := map[string]UVMapping{
+		"planar":      PlanarMap,
+		"spherical":   SphericalMap,
+		"cylindrical": CylindricalMap,
+		"cube":        CubeMap,
+	}
+
+	mapping, found := mappings[mappingNode.Value]
+	if mappingNode.Kind != yaml.ScalarNode || !found {
+		return nil, loader.errorf(mappingNode, "unknown mapping %q, expected planar, spherical, cylindrical or cube", mappingNode.Value)
+	}
+
+	texture, err := loader.uvPattern(uvPatternNode)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTextureMapPattern(texture, mapping), nil
+}
+
+// either checkers with width, height and colors or an image with a file
+// and an optional filter
+func (loader *loader) uvPattern(node *yaml.Node) (UVPattern, error) {
+	node, err := loader.resolve(node)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := loader.fields(node, "uv-pattern")
+	if err != nil {
+		return nil, err
+	}
+
+	kindNode := findKey(node, "type")
+	if kindNode == nil {
+		return nil, loader.errorf(node, "uv-pattern has no type")
+	}
+
+	allowed := map[string][]string{
+		"checkers": {"width", "height", "colors"},
+		"image":    {"file", "filter"},
+	}
+
+	keys, found := allowed[kindNode.Value]
+	if !found {
+		return nil, loader.errorf(kindNode, "unknown uv-pattern %q", kindNode.Value)
+	}
+
+	width, height := 1.0, 1.0
+	colors := []Color{Black, White}
+	file := ""
+	filter := FilterBilinear
+
+	for _, field := range fields {
+		key := field.key.Value
+
+		if key != "type" && !contains(keys, key) {
+			return nil, loader.errorf(field.key, "unknown key %q in %s uv-pattern", key, kindNode.Value)
+		}
+
+		switch key {
+		case "width":
+			width, err = loader.float(field.value)
+		case "height":
+			height, err = loader.float(field.value)
+		case "colors":
+			colors, err = loader.colors(field.value, 2)
+		case "file":
+			file, err = loader.string(field.value)
+		case "filter":
+			switch field.value.Value {
+			case "nearest":
+				filter = FilterNearest
+			case "bilinear":
+				filter = FilterBilinear
+			default:
+				err = loader.errorf(field.value, "unknown filter %q, expected nearest or bilinear", field.value.Value)
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if kindNode.Value == "checkers" {
+		return NewUVCheckers(width, height, colors[0], colors[1]), nil
+	}
+
+	if file == "" {
+		return nil, loader.errorf(node, "image uv-pattern needs a file")
+	}
+
+	canvas, err := image.LoadFile(loader.path(file))
+	if err != nil {
+		return nil, loader.errorf(findKey(node, "file"), "%s", err)
+	}
+
+	return NewImageTexture(canvas, filter), nil
+}
+
+func (loader *loader) colors(node *yaml.Node, count int) ([]Color, error) {
+	if node.Kind != yaml.SequenceNode || len(node.Content) != count {
+		return nil, loader.errorf(node, "expected a list of %d colors", count)
+	}
+
+	colors := []Color{}
+
+	for _, item := range node.Content {
+		color, err := loader.color(item)
+		if err != nil {
+			return nil, err
+		}
+
+		colors = append(colors, color)
+	}
+
+	return colors, nil
+}
+
+func (loader *loader) subpatterns(node *yaml.Node, depth int) ([]Pattern, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, loader.errorf(node, "expected a list of patterns")
+	}
+
+	patterns := []Pattern{}
+
+	for _, item := range node.Content {
+		pattern, err := loader.pattern(item, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, nil
+}
+
+// pattern that alternates or blends between a and b
+func newBlendPattern(kind string, a Pattern, b Pattern) (Pattern, *PatternImpl) {
+	switch kind {
+	case "stripes":
+		pattern := NewStripePattern(a, b)
+		return pattern, &pattern.PatternImpl
+	case "gradient":
+		pattern := NewGradientPattern(a, b)
+		return pattern, &pattern.PatternImpl
+	case "rings":
+		pattern := NewRingPattern(a, b)
+		return pattern, &pattern.PatternImpl
+	}
+
+	pattern := NewCheckersPattern(a, b)
+	return pattern, &pattern.PatternImpl
+}
+
+// a list of operations such as [translate, 1, 2, 3], or names of defined
+// lists, applied like the equivalent chain of core.Matrix methods
+func (loader *loader) transform(node *yaml.Node) (Matrix, error) {
+	matrix, err := loader.applyTransforms(NewIdentityMatrix(), node, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if !matrix.Invertible() {
+		return nil, loader.errorf(node, "transform is not invertible")
+	}
+
+	return matrix, nil
+}
+
+func (loader *loader) applyTransforms(matrix Matrix, node *yaml.Node, depth int) (Matrix, error) {
+	if depth > maxDefinitionDepth {
+		return nil, loader.errorf(node, "definitions nested too deeply")
+	}
+
+	node, err := loader.resolve(node)
+	if err != nil {
+		return nil, err
+	}
+
+	if node.Kind != yaml.SequenceNode {
+		return nil, loader.errorf(node, "expected a list of transforms")
+	}
+
+	for _, item := range node.Content {
+		if item.Kind == yaml.ScalarNode {
+			matrix, err = loader.applyTransforms(matrix, item, depth+1)
+		} else {
+			matrix, err = loader.applyTransform(matrix, item)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return matrix, nil
+}
+
+func (loader *loader) applyTransform(matrix Matrix, node *yaml.Node) (Matrix, error) {
+	if node.Kind != yaml.SequenceNode || len(node.Content) == 0 {
+		return nil, loader.errorf(node, "expected a transform such as [translate, x, y, z]")
+	}
+
+	operation := node.Content[0].Value
+	counts := map[string]int{
+		"translate": 3, "scale": 3,
+		"rotate-x": 1, "rotate-y": 1, "rotate-z": 1,
+		"shear": 6,
+	}
+
+	count, found := counts[operation]
+	if !found {
+		return nil, loader.errorf(node.Content[0], "unknown transform %q", operation)
+	}
+
+	args := make([]float64, len(node.Content)-1)
+	if len(args) != count {
+		return nil, loader.errorf(node, "%s needs %d numbers, got %d", operation, count, len(args))
+	}
+
+	for i, item := range node.Content[1:] {
+		value, err := loader.float(item)
+		if err != nil {
+			return nil, err
+		}
+
+		args[i] = value
+	}
+
+	switch operation {
+	case "translate":
+		return matrix.Translate(args[0], args[1], args[2]), nil
+	case "scale":
+		return matrix.Scale(args[0], args[1], args[2]), nil
+	case "rotate-x":
+		return matrix.RotateX(args[0]), nil
+	case "rotate-y":
+		return matrix.RotateY(args[0]), nil
+	case "rotate-z":
+		return matrix.RotateZ(args[0]), nil
+	}
+
+	return matrix.Shear(args[0], args[1], args[2], args[3], args[4], args[5]), nil
+}
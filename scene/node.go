@@ -0,0 +1,172 @@
+package scene
+
+import (
+	"fmt"
+	. "go-raytracer/core"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// position of a problem in a scene file
+type Error struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (err *Error) Error() string {
+	if err.Line == 0 {
+		return fmt.Sprintf("%s: %s", err.File, err.Message)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s", err.File, err.Line, err.Column, err.Message)
+}
+
+func (loader *loader) errorf(node *yaml.Node, format string, args ...interface{}) error {
+	return &Error{loader.file, node.Line, node.Column, fmt.Sprintf(format, args...)}
+}
+
+type field struct {
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+// key value pairs of a mapping, in the order they appear
+func (loader *loader) fields(node *yaml.Node, what string) ([]field, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, loader.errorf(node, "expected a mapping for %s", what)
+	}
+
+	fields := []field{}
+	seen := map[string]bool{}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if seen[key.Value] {
+			return nil, loader.errorf(key, "duplicate key %q in %s", key.Value, what)
+		}
+
+		seen[key.Value] = true
+		fields = append(fields, field{key, node.Content[i+1]})
+	}
+
+	return fields, nil
+}
+
+// mapping with the keys of base, replaced or extended by the keys of override
+func merge(base *yaml.Node, override *yaml.Node) *yaml.Node {
+	result := *override
+	result.Content = []*yaml.Node{}
+
+	for i := 0; i+1 < len(base.Content); i += 2 {
+		if findKey(override, base.Content[i].Value) == nil {
+			result.Content = append(result.Content, base.Content[i], base.Content[i+1])
+		}
+	}
+
+	result.Content = append(result.Content, override.Content...)
+
+	return &result
+}
+
+func findKey(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func (loader *loader) string(node *yaml.Node) (string, error) {
+	if node.Kind != yaml.ScalarNode {
+		return "", loader.errorf(node, "expected a string")
+	}
+
+	return node.Value, nil
+}
+
+func (loader *loader) float(node *yaml.Node) (float64, error) {
+	if node.Kind != yaml.ScalarNode {
+		return 0, loader.errorf(node, "expected a number")
+	}
+
+	value, err := strconv.ParseFloat(node.Value, 64)
+	if err != nil {
+		return 0, loader.errorf(node, "expected a number, got %q", node.Value)
+	}
+
+	return value, nil
+}
+
+func (loader *loader) uint(node *yaml.Node) (uint, error) {
+	if node.Kind != yaml.ScalarNode {
+		return 0, loader.errorf(node, "expected a positive integer")
+	}
+
+	value, err := strconv.ParseUint(node.Value, 10, 0)
+	if err != nil || value == 0 {
+		return 0, loader.errorf(node, "expected a positive integer, got %q", node.Value)
+	}
+
+	return uint(value), nil
+}
+
+func (loader *loader) bool(node *yaml.Node) (bool, error) {
+	var value bool
+
+	if node.Kind != yaml.ScalarNode || node.Decode(&value) != nil {
+		return false, loader.errorf(node, "expected true or false, got %q", node.Value)
+	}
+
+	return value, nil
+}
+
+func (loader *loader) floats(node *yaml.Node, count int) ([]float64, error) {
+	if node.Kind != yaml.SequenceNode || len(node.Content) != count {
+		return nil, loader.errorf(node, "expected a list of %d numbers", count)
+	}
+
+	values := make([]float64, count)
+
+	for i, item := range node.Content {
+		value, err := loader.float(item)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+func (loader *loader) point(node *yaml.Node) (Tuple, error) {
+	values, err := loader.floats(node, 3)
+	if err != nil {
+		return Tuple{}, err
+	}
+
+	return NewPoint(values[0], values[1], values[2]), nil
+}
+
+func (loader *loader) vector(node *yaml.Node) (Tuple, error) {
+	values, err := loader.floats(node, 3)
+	if err != nil {
+		return Tuple{}, err
+	}
+
+	return NewVector(values[0], values[1], values[2]), nil
+}
+
+func (loader *loader) color(node *yaml.Node) (Color, error) {
+	values, err := loader.floats(node, 3)
+	if err != nil {
+		return Color{}, err
+	}
+
+	return NewColor(values[0], values[1], values[2]), nil
+}
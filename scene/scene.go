@@ -0,0 +1,446 @@
+package scene
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	. "go-raytracer/physics"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// world and camera described by a YAML scene file, a list of items that
+// either add something to the scene or define a reusable value:
+//
+//	# scene.yml
+//	- add: camera
+//	  width: 100
+//	  height: 50
+//	  field-of-view: 1.0472
+//	  from: [0, 1.5, -5]
+//	  to: [0, 1, 0]
+//	  up: [0, 1, 0]
+//	- add: light
+//	  at: [-10, 10, -10]
+//	  intensity: [1, 1, 1]
+//	- define: shiny
+//	  value:
+//	    reflective: 0.5
+//	- add: sphere
+//	  material: shiny
+//	  transform:
+//	    - [translate, 0, 1, 0]
+//	    - [scale, 0.5, 0.5, 0.5]
+//
+// transforms are applied in the same order as the equivalent chain of
+// core.Matrix methods
+type Scene struct {
+	World  World
+	Camera Camera
+}
+
+type loader struct {
+	file        string
+	dir         string
+	definitions map[string]*yaml.Node
+	scene       Scene
+	hasCamera   bool
+}
+
+func LoadFile(path string) (*Scene, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data, path)
+}
+
+// parses a scene, file is used in errors and to find OBJ files relative to it
+func Parse(data []byte, file string) (*Scene, error) {
+	loader := loader{file: file, dir: filepath.Dir(file), definitions: map[string]*yaml.Node{}}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, &Error{file, 0, 0, err.Error()}
+	}
+
+	if document.Kind == 0 {
+		return nil, &Error{file, 1, 1, "scene is empty"}
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.SequenceNode {
+		return nil, loader.errorf(root, "expected a list of scene items")
+	}
+
+	for _, item := range root.Content {
+		if err := loader.item(item); err != nil {
+			return nil, err
+		}
+	}
+
+	if !loader.hasCamera {
+		return nil, loader.errorf(root, "scene has no camera")
+	}
+
+	loader.scene.World.BuildBVH(SplitSAH)
+
+	return &loader.scene, nil
+}
+
+func (loader *loader) item(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return loader.errorf(node, "expected a mapping with add or define")
+	}
+
+	if name := findKey(node, "define"); name != nil {
+		return loader.define(node, name)
+	}
+
+	kind := findKey(node, "add")
+	if kind == nil {
+		return loader.errorf(node, "expected a mapping with add or define")
+	}
+
+	switch kind.Value {
+	case "camera":
+		return loader.camera(node)
+	case "light", "area-light", "spot-light", "directional-light":
+		light, err := loader.light(node, kind.Value)
+		if err != nil {
+			return err
+		}
+
+		loader.scene.World.Lights = append(loader.scene.World.Lights, light)
+		return nil
+	}
+
+	shape, err := loader.shape(node)
+	if err != nil {
+		return err
+	}
+
+	loader.scene.World.AddObject(shape)
+	return nil
+}
+
+// defines a named value, mappings may extend an earlier definition
+func (loader *loader) define(node *yaml.Node, nameNode *yaml.Node) error {
+	fields, err := loader.fields(node, "definition")
+	if err != nil {
+		return err
+	}
+
+	name, err := loader.string(nameNode)
+	if err != nil {
+		return err
+	}
+
+	var value *yaml.Node
+	var extend *yaml.Node
+
+	for _, field := range fields {
+		switch field.key.Value {
+		case "define":
+		case "value":
+			value = field.value
+		case "extend":
+			extend = field.value
+		default:
+			return loader.errorf(field.key, "unknown key %q in definition", field.key.Value)
+		}
+	}
+
+	if value == nil {
+		return loader.errorf(node, "definition %q has no value", name)
+	}
+
+	if extend != nil {
+		base, err := loader.lookup(extend)
+		if err != nil {
+			return err
+		}
+
+		if base.Kind != yaml.MappingNode || value.Kind != yaml.MappingNode {
+			return loader.errorf(extend, "only mappings can be extended")
+		}
+
+		value = merge(base, value)
+	}
+
+	loader.definitions[name] = value
+	return nil
+}
+
+func (loader *loader) lookup(node *yaml.Node) (*yaml.Node, error) {
+	name, err := loader.string(node)
+	if err != nil {
+		return nil, err
+	}
+
+	value, found := loader.definitions[name]
+	if !found {
+		return nil, loader.errorf(node, "undefined name %q", name)
+	}
+
+	return value, nil
+}
+
+// replaces a name with its definition
+func (loader *loader) resolve(node *yaml.Node) (*yaml.Node, error) {
+	if node.Kind == yaml.ScalarNode {
+		return loader.lookup(node)
+	}
+
+	return node, nil
+}
+
+func (loader *loader) camera(node *yaml.Node) error {
+	fields, err := loader.fields(node, "camera")
+	if err != nil {
+		return err
+	}
+
+	var width, height uint
+	var fieldOfView float64
+	from := NewPoint(0, 0, 0)
+	to := NewPoint(0, 0, -1)
+	up := NewVector(0, 1, 0)
+	aperture, focalDistance, rotation := 0.0, 1.0, 0.0
+	var blades uint
+	projection := "perspective"
+	var viewWidth float64
+
+	for _, field := range fields {
+		switch field.key.Value {
+		case "add":
+		case "width":
+			width, err = loader.uint(field.value)
+		case "height":
+			height, err = loader.uint(field.value)
+		case "field-of-view":
+			fieldOfView, err = loader.float(field.value)
+		case "from":
+			from, err = loader.point(field.value)
+		case "to":
+			to, err = loader.point(field.value)
+		case "up":
+			up, err = loader.vector(field.value)
+		case "aperture":
+			aperture, err = loader.float(field.value)
+		case "focal-distance":
+			focalDistance, err = loader.float(field.value)
+		case "aperture-blades":
+			blades, err = loader.uint(field.value)
+		case "aperture-rotation":
+			rotation, err = loader.float(field.value)
+		case "projection":
+			projection, err = loader.string(field.value)
+			if err == nil && !contains([]string{"perspective", "orthographic", "fisheye", "equirectangular"}, projection) {
+				err = loader.errorf(field.value, "unknown projection %q, expected perspective, orthographic, fisheye or equirectangular", projection)
+			}
+		case "view-width":
+			viewWidth, err = loader.float(field.value)
+		default:
+			err = loader.errorf(field.key, "unknown key %q in camera", field.key.Value)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if width == 0 || height == 0 {
+		return loader.errorf(node, "camera needs width and height")
+	}
+
+	// the other projections do not use a field of view
+	if (projection == "perspective" || projection == "fisheye") && fieldOfView <= 0 {
+		return loader.errorf(node, "%s camera needs a field-of-view", projection)
+	}
+
+	if projection == "orthographic" && viewWidth <= 0 {
+		return loader.errorf(node, "orthographic camera needs a positive view-width")
+	}
+
+	if aperture < 0 || focalDistance <= 0 {
+		return loader.errorf(node, "camera needs an aperture of at least 0 and a positive focal-distance")
+	}
+
+	if loader.hasCamera {
+		return loader.errorf(node, "scene has more than one camera")
+	}
+
+	loader.scene.Camera = NewCamera(width, height, fieldOfView)
+	loader.scene.Camera.Transform = ViewTransform(from, to, up)
+	loader.scene.Camera.Aperture = aperture
+	loader.scene.Camera.FocalDistance = focalDistance
+	loader.scene.Camera.ApertureBlades = blades
+	loader.scene.Camera.ApertureRotation = rotation
+
+	switch projection {
+	case "orthographic":
+		loader.scene.Camera.Projection = OrthographicProjection{Width: viewWidth}
+	case "fisheye":
+		loader.scene.Camera.Projection = FisheyeProjection{}
+	case "equirectangular":
+		loader.scene.Camera.Projection = EquirectangularProjection{}
+	}
+
+	loader.hasCamera = true
+
+	return nil
+}
+
+func (loader *loader) light(node *yaml.Node, kind string) (Light, error) {
+	fields, err := loader.fields(node, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	intensity := White
+	position := NewPoint(0, 0, 0)
+	direction := NewVector(0, -1, 0)
+	corner := NewPoint(0, 0, 0)
+	uvec := NewVector(1, 0, 0)
+	vvec := NewVector(0, 0, 1)
+	usteps, vsteps := uint(1), uint(1)
+	jitter := true
+	innerAngle, outerAngle := 0.0, 0.0
+	attenuation := Attenuation{}
+
+	// keys each kind of light accepts besides add, intensity and attenuation
+	allowed := map[string][]string{
+		"light":             {"at"},
+		"area-light":        {"corner", "uvec", "vvec", "usteps", "vsteps", "jitter"},
+		"spot-light":        {"at", "direction", "inner-angle", "outer-angle"},
+		"directional-light": {"direction"},
+	}
+
+	for _, field := range fields {
+		key := field.key.Value
+
+		if key != "add" && key != "intensity" && key != "attenuation" && !contains(allowed[kind], key) {
+			return nil, loader.errorf(field.key, "unknown key %q in %s", key, kind)
+		}
+
+		switch key {
+		case "intensity":
+			intensity, err = loader.color(field.value)
+		case "attenuation":
+			attenuation, err = loader.attenuation(field.value)
+		case "at":
+			position, err = loader.point(field.value)
+		case "direction":
+			direction, err = loader.vector(field.value)
+		case "corner":
+			corner, err = loader.point(field.value)
+		case "uvec":
+			uvec, err = loader.vector(field.value)
+		case "vvec":
+			vvec, err = loader.vector(field.value)
+		case "usteps":
+			usteps, err = loader.uint(field.value)
+		case "vsteps":
+			vsteps, err = loader.uint(field.value)
+		case "jitter":
+			jitter, err = loader.bool(field.value)
+		case "inner-angle":
+			innerAngle, err = loader.float(field.value)
+		case "outer-angle":
+			outerAngle, err = loader.float(field.value)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch kind {
+	case "area-light":
+		light := NewAreaLight(corner, uvec, usteps, vvec, vsteps, intensity)
+		light.Jitter = jitter
+		light.Attenuation = attenuation
+		return light, nil
+	case "spot-light":
+		if outerAngle <= innerAngle {
+			return nil, loader.errorf(node, "spot-light outer-angle must be larger than inner-angle")
+		}
+
+		light := NewSpotLight(position, direction, innerAngle, outerAngle, intensity)
+		light.Attenuation = attenuation
+		return light, nil
+	case "directional-light":
+		light := NewDirectionalLight(direction, intensity)
+		light.Attenuation = attenuation
+		return light, nil
+	}
+
+	light := NewPointLight(position, intensity)
+	light.Attenuation = attenuation
+	return light, nil
+}
+
+// either none, inverse-square or a mapping of constant, linear, quadratic and radius
+func (loader *loader) attenuation(node *yaml.Node) (Attenuation, error) {
+	if node.Kind == yaml.ScalarNode {
+		switch node.Value {
+		case "none":
+			return Attenuation{}, nil
+		case "inverse-square":
+			return NewInverseSquareAttenuation(), nil
+		}
+
+		return Attenuation{}, loader.errorf(node, "unknown attenuation %q", node.Value)
+	}
+
+	fields, err := loader.fields(node, "attenuation")
+	if err != nil {
+		return Attenuation{}, err
+	}
+
+	var constant, linear, quadratic, radius float64
+
+	for _, field := range fields {
+		switch field.key.Value {
+		case "constant":
+			constant, err = loader.float(field.value)
+		case "linear":
+			linear, err = loader.float(field.value)
+		case "quadratic":
+			quadratic, err = loader.float(field.value)
+		case "radius":
+			radius, err = loader.float(field.value)
+		default:
+			err = loader.errorf(field.key, "unknown key %q in attenuation", field.key.Value)
+		}
+
+		if err != nil {
+			return Attenuation{}, err
+		}
+	}
+
+	if constant < 0 || linear < 0 || quadratic < 0 || radius < 0 {
+		return Attenuation{}, loader.errorf(node, "attenuation terms and radius cannot be negative")
+	}
+
+	// a mapping that sets nothing would attenuate nothing, which none says
+	// more plainly
+	if constant == 0 && linear == 0 && quadratic == 0 && radius == 0 {
+		return Attenuation{}, loader.errorf(node, "attenuation needs a positive constant, linear, quadratic or radius, use none for no attenuation")
+	}
+
+	return NewAttenuation(constant, linear, quadratic, radius), nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
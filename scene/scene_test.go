@@ -0,0 +1,512 @@
+package scene
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	"go-raytracer/image"
+	. "go-raytracer/physics"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const cameraYaml = `
+- add: camera
+  width: 100
+  height: 50
+  field-of-view: 0.785
+  from: [-6, 6, -10]
+  to: [6, 0, 6]
+  up: [-0.45, 1, 0]
+`
+
+func parse(t *testing.T, data string) *Scene {
+	scene, err := Parse([]byte(cameraYaml+data), "scene.yml")
+	assert.NoError(t, err)
+
+	return scene
+}
+
+func parseError(t *testing.T, data string) string {
+	_, err := Parse([]byte(data), "scene.yml")
+	assert.Error(t, err)
+
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+func TestParseCamera(t *testing.T) {
+	scene := parse(t, "")
+
+	expected := NewCamera(100, 50, 0.785)
+	expected.Transform = ViewTransform(NewPoint(-6, 6, -10), NewPoint(6, 0, 6), NewVector(-0.45, 1, 0))
+
+	assert.Equal(t, expected, scene.Camera)
+	assert.Empty(t, scene.World.Objects)
+	assert.Empty(t, scene.World.Lights)
+}
+
+func TestParseCameraLens(t *testing.T) {
+	scene, err := Parse([]byte(`
+- add: camera
+  width: 10
+  height: 10
+  field-of-view: 1
+  aperture: 0.2
+  focal-distance: 6.5
+  aperture-blades: 5
+  aperture-rotation: 0.3
+`), "scene.yml")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.2, scene.Camera.Aperture)
+	assert.Equal(t, 6.5, scene.Camera.FocalDistance)
+	assert.Equal(t, uint(5), scene.Camera.ApertureBlades)
+	assert.Equal(t, 0.3, scene.Camera.ApertureRotation)
+}
+
+func TestParseCameraProjection(t *testing.T) {
+	tests := []struct {
+		yaml     string
+		expected Projection
+	}{
+		{"field-of-view: 1", PerspectiveProjection{}},
+		{"projection: orthographic\n  view-width: 8", OrthographicProjection{Width: 8}},
+		{"projection: fisheye\n  field-of-view: 3.1416", FisheyeProjection{}},
+		{"projection: equirectangular", EquirectangularProjection{}},
+	}
+
+	for _, test := range tests {
+		scene, err := Parse([]byte("- add: camera\n  width: 20\n  height: 10\n  "+test.yaml), "scene.yml")
+
+		assert.NoError(t, err, test.yaml)
+		assert.Equal(t, test.expected, scene.Camera.Projection, test.yaml)
+	}
+}
+
+func TestParseLights(t *testing.T) {
+	scene := parse(t, `
+- add: light
+  at: [50, 100, -50]
+  intensity: [1, 1, 1]
+- add: area-light
+  corner: [-1, 2, 4]
+  uvec: [2, 0, 0]
+  vvec: [0, 2, 0]
+  usteps: 4
+  vsteps: 2
+  jitter: false
+  intensity: [1.5, 1.5, 1.5]
+- add: spot-light
+  at: [0, 10, 0]
+  direction: [0, -1, 0]
+  inner-angle: 0.2
+  outer-angle: 0.4
+  attenuation: inverse-square
+- add: directional-light
+  direction: [1, -1, 0]
+  intensity: [0.2, 0.2, 0.2]
+  attenuation:
+    constant: 1
+    linear: 0.5
+`)
+
+	point := NewPointLight(NewPoint(50, 100, -50), White)
+
+	area := NewAreaLight(NewPoint(-1, 2, 4), NewVector(2, 0, 0), 4, NewVector(0, 2, 0), 2, NewColor(1.5, 1.5, 1.5))
+	area.Jitter = false
+
+	spot := NewSpotLight(NewPoint(0, 10, 0), NewVector(0, -1, 0), 0.2, 0.4, White)
+	spot.Attenuation = NewInverseSquareAttenuation()
+
+	directional := NewDirectionalLight(NewVector(1, -1, 0), NewColor(0.2, 0.2, 0.2))
+	directional.Attenuation = Attenuation{Constant: 1, Linear: 0.5}
+
+	assert.Equal(t, []Light{point, area, spot, directional}, scene.World.Lights)
+}
+
+func TestParseShapeWithMaterialAndTransform(t *testing.T) {
+	scene := parse(t, `
+- add: sphere
+  material:
+    color: [1, 0.2, 1]
+    ambient: 0.1
+    diffuse: 0.6
+    specular: 0.3
+    shininess: 15
+    reflective: 0.1
+    transparency: 0.5
+    refractive-index: 1.5
+    roughness: 0.2
+    absorption: [0.1, 0.5, 0.9]
+    absorption-density: 2
+  transform:
+    - [translate, 1, 2, 3]
+    - [scale, 2, 2, 2]
+    - [rotate-y, 0.5]
+    - [shear, 1, 0, 0, 0, 0, 0]
+`)
+
+	expected := NewSphere()
+	expected.Material.Color = NewColor(1, 0.2, 1)
+	expected.Material.Ambient = 0.1
+	expected.Material.Diffuse = 0.6
+	expected.Material.Specular = 0.3
+	expected.Material.Shininess = 15
+	expected.Material.Reflective = 0.1
+	expected.Material.Transparency = 0.5
+	expected.Material.RefractiveIndex = 1.5
+	expected.Material.Roughness = 0.2
+	expected.Material.Absorption = NewColor(0.1, 0.5, 0.9)
+	expected.Material.AbsorptionDensity = 2
+	expected.Transform = NewIdentityMatrix().Translate(1, 2, 3).Scale(2, 2, 2).RotateY(0.5).Shear(1, 0, 0, 0, 0, 0)
+
+	assert.Len(t, scene.World.Objects, 1)
+
+	sphere := scene.World.Objects[0].(*Sphere)
+	assert.Equal(t, expected.Material, sphere.Material)
+	assert.True(t, expected.Transform.Equals(sphere.Transform))
+}
+
+func TestParsePBRMaterial(t *testing.T) {
+	scene := parse(t, `
+- add: sphere
+  material:
+    pbr:
+      base-color: [0.9, 0.6, 0.2]
+      metallic: 1
+      roughness: 0.25
+      specular: 0.4
+      roughness-map: [0.5, 0.5, 0.5]
+- add: sphere
+  material:
+    pbr: {}
+`)
+
+	expected := NewPBR(NewColor(0.9, 0.6, 0.2), 1, 0.25)
+	expected.Specular = 0.4
+	expected.RoughnessMap = NewSolidPattern(NewColor(0.5, 0.5, 0.5))
+
+	assert.Equal(t, expected, scene.World.Objects[0].GetMaterial().PBR)
+	assert.Equal(t, NewPBR(White, 0, 0.5), scene.World.Objects[1].GetMaterial().PBR)
+}
+
+func TestParseEmissiveShapeLight(t *testing.T) {
+	scene := parse(t, `
+- add: group
+  light-samples: 4
+  material:
+    emission: [1, 0.8, 0.6]
+    emission-strength: 5
+  children:
+    - add: triangle
+      p1: [0, 2, 0]
+      p2: [1, 2, 0]
+      p3: [0, 2, 1]
+- add: sphere
+`)
+
+	group := scene.World.Objects[0]
+	assert.Equal(t, NewColor(1, 0.8, 0.6), group.GetMaterial().Emission)
+	assert.Equal(t, 5.0, group.GetMaterial().EmissionStrength)
+
+	assert.Len(t, scene.World.Lights, 1)
+	light := scene.World.Lights[0].(*ShapeLight)
+	assert.Equal(t, group, light.GetShape())
+	assert.Equal(t, NewColor(5, 4, 3), light.GetIntensity())
+	// the triangle faces up
+	assert.Len(t, light.Samples(NewPoint(0, 4, 0)), 4)
+}
+
+func TestParseDefinitions(t *testing.T) {
+	scene := parse(t, `
+- define: white-material
+  value:
+    color: [1, 1, 1]
+    diffuse: 0.7
+- define: blue-material
+  extend: white-material
+  value:
+    color: [0.5, 0.5, 1]
+- define: standard-transform
+  value:
+    - [translate, 1, -1, 1]
+    - [scale, 0.5, 0.5, 0.5]
+- define: large-object
+  value:
+    - standard-transform
+    - [scale, 3.5, 3.5, 3.5]
+- add: cube
+  material: blue-material
+  transform: large-object
+- add: cube
+  material: white-material
+  transform:
+    - large-object
+    - [translate, 4, 0, 0]
+`)
+
+	blue := NewMaterial()
+	blue.Color = NewColor(0.5, 0.5, 1)
+	blue.Diffuse = 0.7
+
+	white := NewMaterial()
+	white.Diffuse = 0.7
+
+	large := NewIdentityMatrix().Translate(1, -1, 1).Scale(0.5, 0.5, 0.5).Scale(3.5, 3.5, 3.5)
+
+	first := scene.World.Objects[0].(*Cube)
+	second := scene.World.Objects[1].(*Cube)
+
+	assert.Equal(t, blue, first.Material)
+	assert.True(t, large.Equals(first.Transform))
+	assert.Equal(t, white, second.Material)
+	assert.True(t, large.Translate(4, 0, 0).Equals(second.Transform))
+}
+
+func TestParseDefinedShape(t *testing.T) {
+	scene := parse(t, `
+- define: pillar
+  value:
+    add: cylinder
+    minimum: 0
+    maximum: 3
+    closed: true
+- add: pillar
+  transform:
+    - [translate, 2, 0, 0]
+`)
+
+	cylinder := scene.World.Objects[0].(*Cylinder)
+
+	assert.Equal(t, 0.0, cylinder.Minimum)
+	assert.Equal(t, 3.0, cylinder.Maximum)
+	assert.True(t, cylinder.Closed)
+	assert.True(t, NewIdentityMatrix().Translate(2, 0, 0).Equals(cylinder.Transform))
+}
+
+func TestParseNestedPatterns(t *testing.T) {
+	scene := parse(t, `
+- define: stripes
+  value:
+    type: stripes
+    patterns:
+      - [1, 0, 0]
+      - [0, 0, 1]
+    transform:
+      - [scale, 0.25, 0.25, 0.25]
+- add: plane
+  material:
+    pattern:
+      type: checkers
+      patterns:
+        - stripes
+        - type: solid
+          color: [0, 1, 0]
+`)
+
+	stripes := NewStripePattern(NewSolidPattern(Red), NewSolidPattern(Blue))
+	stripes.Transform = NewIdentityMatrix().Scale(0.25, 0.25, 0.25)
+	expected := NewCheckersPattern(stripes, NewSolidPattern(Green))
+
+	plane := scene.World.Objects[0].(*Plane)
+	assert.Equal(t, Pattern(expected), plane.Material.Pattern)
+}
+
+func TestParseTextureMaps(t *testing.T) {
+	dir := t.TempDir()
+
+	texture := image.NewCanvas(2, 1)
+	texture.WritePixel(1, 0, Red)
+	assert.NoError(t, texture.SaveFile(filepath.Join(dir, "texture.png"), 0))
+
+	path := filepath.Join(dir, "scene.yml")
+	assert.NoError(t, os.WriteFile(path, []byte(cameraYaml+`
+- add: sphere
+  material:
+    pattern:
+      type: map
+      mapping: spherical
+      uv-pattern:
+        type: checkers
+        width: 16
+        height: 8
+        colors: [[0, 0, 0], [1, 1, 1]]
+- add: cube
+  material:
+    pattern:
+      type: map
+      mapping: cube
+      uv-pattern:
+        type: image
+        file: texture.png
+        filter: nearest
+`), 0o644))
+
+	scene, err := LoadFile(path)
+	assert.NoError(t, err)
+
+	sphere := scene.World.Objects[0].(*Sphere)
+	expected := NewTextureMapPattern(NewUVCheckers(16, 8, Black, White), SphericalMap)
+	for _, point := range []Tuple{NewPoint(0.4315, 0.4670, 0.7719), NewPoint(-0.9654, 0.2552, -0.0534)} {
+		assert.Equal(t, expected.ColorAt(point), sphere.Material.Pattern.ColorAt(point))
+	}
+
+	cube := scene.World.Objects[1].(*Cube)
+	assert.Equal(t, Black, cube.Material.Pattern.ColorAt(NewPoint(-1, 0, 0)))
+	assert.Equal(t, Red, cube.Material.Pattern.ColorAt(NewPoint(0, 0, -1)))
+}
+
+func TestParseTriangleGroupAndCSG(t *testing.T) {
+	scene := parse(t, `
+- add: group
+  transform:
+    - [translate, 0, 1, 0]
+  children:
+    - add: triangle
+      p1: [0, 1, 0]
+      p2: [-1, 0, 0]
+      p3: [1, 0, 0]
+    - add: csg
+      operation: difference
+      left:
+        add: cube
+      right:
+        add: sphere
+        transform:
+          - [scale, 1.5, 1.5, 1.5]
+`)
+
+	group := scene.World.Objects[0].(*Group)
+	assert.Len(t, group.Children, 2)
+	assert.True(t, NewIdentityMatrix().Translate(0, 1, 0).Equals(group.Transform))
+
+	triangle := group.Children[0].(*Triangle)
+	assert.Equal(t, NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0)).Bounds(), triangle.Bounds())
+	assert.Equal(t, Shape(group), triangle.GetParent())
+
+	csg := group.Children[1].(*CSG)
+	assert.Equal(t, CSGDifference, csg.Operation)
+	assert.IsType(t, &Cube{}, csg.Left)
+	assert.IsType(t, &Sphere{}, csg.Right)
+	assert.Equal(t, Shape(group), csg.GetParent())
+}
+
+func TestParseObjRelativeToScene(t *testing.T) {
+	dir := t.TempDir()
+
+	obj := "v -1 1 0\nv -1 0 0\nv 1 0 0\nv 1 1 0\nf 1 2 3 4\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "model.obj"), []byte(obj), 0o644))
+
+	path := filepath.Join(dir, "scene.yml")
+	assert.NoError(t, os.WriteFile(path, []byte(cameraYaml+"- add: obj\n  file: model.obj\n"), 0o644))
+
+	scene, err := LoadFile(path)
+	assert.NoError(t, err)
+
+	group := scene.World.Objects[0].(*Group)
+	assert.Len(t, group.Children, 2)
+}
+
+func TestRenderParsedScene(t *testing.T) {
+	scene, err := Parse([]byte(`
+- add: camera
+  width: 11
+  height: 11
+  field-of-view: 1.5707963267948966
+  from: [0, 0, -5]
+  to: [0, 0, 0]
+  up: [0, 1, 0]
+- add: light
+  at: [-10, 10, -10]
+- add: sphere
+  material:
+    color: [0.8, 1.0, 0.6]
+    diffuse: 0.7
+    specular: 0.2
+- add: sphere
+  transform:
+    - [scale, 0.5, 0.5, 0.5]
+`), "scene.yml")
+	assert.NoError(t, err)
+
+	image := scene.Camera.Render(scene.World)
+
+	assert.True(t, NewColor(0.38066, 0.47583, 0.2855).Equals(image.Pixel[5][5]))
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		yaml     string
+		expected string
+	}{
+		{"", "scene.yml:1:1: scene is empty"},
+		{"add: sphere", "scene.yml:1:1: expected a list of scene items"},
+		{"- add: sphere", "scene.yml:1:1: scene has no camera"},
+		{"- [1, 2]", "scene.yml:1:3: expected a mapping with add or define"},
+		{"- add: sphere\n  colour: [1, 0, 0]", "scene.yml:2:3: unknown key \"colour\" in sphere"},
+		{"- add: sphere\n  material:\n    color: [1, 0]", "scene.yml:3:12: expected a list of 3 numbers"},
+		{"- add: sphere\n  material:\n    diffuse: lots", "scene.yml:3:14: expected a number, got \"lots\""},
+		{"- add: sphere\n  material: shiny", "scene.yml:2:13: undefined name \"shiny\""},
+		{"- add: camera\n  width: 1\n  height: 1\n  field-of-view: 1\n  aperture: -1",
+			"scene.yml:1:3: camera needs an aperture of at least 0 and a positive focal-distance"},
+		{"- add: camera\n  width: 1\n  height: 1", "scene.yml:1:3: perspective camera needs a field-of-view"},
+		{"- add: camera\n  width: 1\n  height: 1\n  projection: orthographic",
+			"scene.yml:1:3: orthographic camera needs a positive view-width"},
+		{"- add: camera\n  width: 1\n  height: 1\n  projection: cylindrical",
+			"scene.yml:4:15: unknown projection \"cylindrical\", expected perspective, orthographic, fisheye or equirectangular"},
+		{"- add: teapot", "scene.yml:1:8: unknown shape \"teapot\""},
+		{"- add: sphere\n  material:\n    roughness: 1.5", "scene.yml:3:16: expected a number from 0 to 1, got 1.5"},
+		{"- add: sphere\n  material:\n    pbr: {metallic: 2}", "scene.yml:3:21: expected a number from 0 to 1, got 2"},
+		{"- add: sphere\n  material:\n    pbr: {shininess: 2}", "scene.yml:3:11: unknown key \"shininess\" in pbr"},
+		{"- add: plane\n  light-samples: 4\n  material: {emission: [1, 1, 1]}",
+			"scene.yml:2:18: a plane cannot be a light, only spheres, cubes, triangles and groups can"},
+		{"- add: sphere\n  light-samples: 4", "scene.yml:2:18: a light needs a material with emission"},
+		{"- add: group\n  light-samples: 4\n  material: {emission: [1, 1, 1]}\n  children: [{add: plane}]",
+			"scene.yml:2:18: a group light needs a surface, with spheres, cubes or triangles in it"},
+		{"- add: sphere\n  light-samples: 0", "scene.yml:2:18: expected a positive integer, got \"0\""},
+		{"- add: sphere\n  transform:\n    - [spin, 1]", "scene.yml:3:8: unknown transform \"spin\""},
+		{"- add: sphere\n  transform:\n    - [translate, 1]", "scene.yml:3:7: translate needs 3 numbers, got 1"},
+		{"- add: sphere\n  transform:\n    - [scale, 0, 1, 1]", "scene.yml:3:5: transform is not invertible"},
+		{"- add: sphere\n  minimum: 1", "scene.yml:2:3: only cylinders and cones have minimum"},
+		{"- add: sphere\n  p1: [0, 0, 0]", "scene.yml:2:3: unknown key \"p1\" in sphere"},
+		{"- add: light\n  corner: [0, 0, 0]", "scene.yml:2:3: unknown key \"corner\" in light"},
+		{"- add: light\n  attenuation: cubic", "scene.yml:2:16: unknown attenuation \"cubic\""},
+		{"- add: light\n  attenuation: {constant: 1, linear: -1}", "scene.yml:2:16: attenuation terms and radius cannot be negative"},
+		{"- add: light\n  attenuation: {constant: 0}",
+			"scene.yml:2:16: attenuation needs a positive constant, linear, quadratic or radius, use none for no attenuation"},
+		{"- add: sphere\n  add: cube", "scene.yml:2:3: duplicate key \"add\" in shape"},
+		{"- define: loop\n  value: {add: loop}\n- add: loop", "scene.yml:3:3: definitions nested too deeply"},
+		{"- define: g\n  value: {add: group, children: [g]}\n- add: g", "scene.yml:2:10: definitions nested too deeply"},
+		{"- define: c\n  value: {add: csg, operation: union, left: c, right: c}\n- add: c", "scene.yml:2:10: definitions nested too deeply"},
+		{"- add: plane\n  material:\n    pattern:\n      type: waves", "scene.yml:4:13: unknown pattern \"waves\""},
+		{"- add: obj\n  file: missing.obj", "scene.yml:2:9: open missing.obj: no such file or directory"},
+		{"- add: plane\n  material:\n    pattern: {type: map, mapping: conical, uv-pattern: {type: checkers}}",
+			"scene.yml:3:35: unknown mapping \"conical\", expected planar, spherical, cylindrical or cube"},
+		{"- add: plane\n  material:\n    pattern: {type: map, mapping: planar, uv-pattern: {type: image, file: missing.png}}",
+			"scene.yml:3:75: open missing.png: no such file or directory"},
+		{"- add: plane\n  material:\n    pattern: {type: map, mapping: planar, uv-pattern: {type: image, width: 2}}",
+			"scene.yml:3:69: unknown key \"width\" in image uv-pattern"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, parseError(t, test.yaml), test.yaml)
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	message := parseError(t, "- add: [sphere")
+
+	assert.Contains(t, message, "scene.yml: yaml:")
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	_, err := LoadFile(filepath.Join(t.TempDir(), "missing.yml"))
+
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,330 @@
+package scene
+
+import (
+	. "go-raytracer/core"
+	. "go-raytracer/geometry"
+	. "go-raytracer/physics"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// limits how deeply definitions may refer to each other, so that a
+// definition that refers to itself is reported instead of looping forever
+const maxDefinitionDepth = 64
+
+var shapeKinds = []string{"sphere", "glass-sphere", "plane", "cube", "cylinder", "cone",
+	"triangle", "group", "csg", "obj"}
+
+func (loader *loader) shape(node *yaml.Node) (Shape, error) {
+	return loader.shapeAt(node, 0)
+}
+
+func (loader *loader) shapeAt(node *yaml.Node, depth int) (Shape, error) {
+	if depth > maxDefinitionDepth {
+		return nil, loader.errorf(node, "definitions nested too deeply")
+	}
+
+	// a name on its own refers to a defined shape
+	if node.Kind == yaml.ScalarNode {
+		base, err := loader.lookup(node)
+		if err != nil {
+			return nil, err
+		}
+
+		return loader.shapeAt(base, depth+1)
+	}
+
+	fields, err := loader.fields(node, "shape")
+	if err != nil {
+		return nil, err
+	}
+
+	kindNode := findKey(node, "add")
+	if kindNode == nil {
+		return nil, loader.errorf(node, "shape has no add key")
+	}
+
+	kind := kindNode.Value
+
+	// a defined shape, with some of its keys replaced
+	if !contains(shapeKinds, kind) {
+		base, err := loader.lookup(kindNode)
+		if err != nil {
+			return nil, loader.errorf(kindNode, "unknown shape %q", kind)
+		}
+
+		if base.Kind != yaml.MappingNode || findKey(base, "add") == nil {
+			return nil, loader.errorf(kindNode, "%q is not a shape", kind)
+		}
+
+		return loader.shapeAt(merge(base, withoutKey(node, "add")), depth+1)
+	}
+
+	shape, err := loader.newShape(node, kind, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	var material, lightSamples *yaml.Node
+
+	for _, field := range fields {
+		switch field.key.Value {
+		case "add":
+		case "material":
+			material = field.value
+		case "light-samples":
+			lightSamples = field.value
+		case "transform":
+			var transform Matrix
+			transform, err = loader.transform(field.value)
+			shape.SetTransform(transform)
+		case "minimum", "maximum", "closed":
+			err = loader.bounds(shape, field)
+		case "p1", "p2", "p3", "children", "operation", "left", "right", "file":
+			// used by newShape
+			if !shapeAccepts(kind, field.key.Value) {
+				err = loader.errorf(field.key, "unknown key %q in %s", field.key.Value, kind)
+			}
+		default:
+			err = loader.errorf(field.key, "unknown key %q in %s", field.key.Value, kind)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if material != nil {
+		value, err := loader.material(material)
+		if err != nil {
+			return nil, err
+		}
+
+		shape.SetMaterial(value)
+	}
+
+	if lightSamples != nil {
+		if err := loader.shapeLight(shape, kind, lightSamples); err != nil {
+			return nil, err
+		}
+	}
+
+	return shape, nil
+}
+
+// adds a light given off by the surface of an emissive shape
+func (loader *loader) shapeLight(shape Shape, kind string, node *yaml.Node) error {
+	samples, err := loader.uint(node)
+	if err != nil {
+		return err
+	}
+
+	sampler, ok := shape.(SurfaceSampler)
+	if !ok {
+		return loader.errorf(node, "a %s cannot be a light, only spheres, cubes, triangles and groups can", kind)
+	}
+
+	if sampler.Area() <= 0 {
+		return loader.errorf(node, "a %s light needs a surface, with spheres, cubes or triangles in it", kind)
+	}
+
+	if shape.GetMaterial().Emitted() == Black {
+		return loader.errorf(node, "a light needs a material with emission")
+	}
+
+	loader.scene.World.Lights = append(loader.scene.World.Lights, NewShapeLight(shape, samples))
+
+	return nil
+}
+
+func shapeAccepts(kind string, key string) bool {
+	switch kind {
+	case "triangle":
+		return key == "p1" || key == "p2" || key == "p3"
+	case "group":
+		return key == "children"
+	case "csg":
+		return key == "operation" || key == "left" || key == "right"
+	case "obj":
+		return key == "file"
+	}
+
+	return false
+}
+
+// depth counts the definitions and shapes the node is nested in
+func (loader *loader) newShape(node *yaml.Node, kind string, depth int) (Shape, error) {
+	switch kind {
+	case "sphere":
+		return NewSphere(), nil
+	case "glass-sphere":
+		return NewGlassSphere(), nil
+	case "plane":
+		return NewPlane(), nil
+	case "cube":
+		return NewCube(), nil
+	case "cylinder":
+		return NewCylinder(), nil
+	case "cone":
+		return NewCone(), nil
+	case "triangle":
+		return loader.triangle(node)
+	case "group":
+		return loader.group(node, depth)
+	case "csg":
+		return loader.csg(node, depth)
+	}
+
+	return loader.obj(node)
+}
+
+func (loader *loader) triangle(node *yaml.Node) (Shape, error) {
+	points := [3]Tuple{}
+
+	for i, key := range []string{"p1", "p2", "p3"} {
+		value := findKey(node, key)
+		if value == nil {
+			return nil, loader.errorf(node, "triangle needs p1, p2 and p3")
+		}
+
+		point, err := loader.point(value)
+		if err != nil {
+			return nil, err
+		}
+
+		points[i] = point
+	}
+
+	return NewTriangle(points[0], points[1], points[2]), nil
+}
+
+func (loader *loader) group(node *yaml.Node, depth int) (Shape, error) {
+	group := NewGroup()
+
+	children := findKey(node, "children")
+	if children == nil {
+		return group, nil
+	}
+
+	if children.Kind != yaml.SequenceNode {
+		return nil, loader.errorf(children, "expected a list of shapes for children")
+	}
+
+	for _, item := range children.Content {
+		child, err := loader.shapeAt(item, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		group.AddChild(child)
+	}
+
+	return group, nil
+}
+
+func (loader *loader) csg(node *yaml.Node, depth int) (Shape, error) {
+	operationNode := findKey(node, "operation")
+	left := findKey(node, "left")
+	right := findKey(node, "right")
+
+	if operationNode == nil || left == nil || right == nil {
+		return nil, loader.errorf(node, "csg needs operation, left and right")
+	}
+
+	var operation CSGOperation
+
+	switch operationNode.Value {
+	case "union":
+		operation = CSGUnion
+	case "intersection":
+		operation = CSGIntersection
+	case "difference":
+		operation = CSGDifference
+	default:
+		return nil, loader.errorf(operationNode, "unknown csg operation %q", operationNode.Value)
+	}
+
+	leftShape, err := loader.shapeAt(left, depth+1)
+	if err != nil {
+		return nil, err
+	}
+
+	rightShape, err := loader.shapeAt(right, depth+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCSG(operation, leftShape, rightShape), nil
+}
+
+func (loader *loader) obj(node *yaml.Node) (Shape, error) {
+	fileNode := findKey(node, "file")
+	if fileNode == nil {
+		return nil, loader.errorf(node, "obj needs a file")
+	}
+
+	path, err := loader.string(fileNode)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := LoadObjFile(loader.path(path))
+	if err != nil {
+		return nil, loader.errorf(fileNode, "%s", err)
+	}
+
+	return obj.ToGroup(), nil
+}
+
+// path relative to the scene file
+func (loader *loader) path(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(loader.dir, path)
+}
+
+func (loader *loader) bounds(shape Shape, field field) error {
+	var err error
+
+	switch shape := shape.(type) {
+	case *Cylinder:
+		err = loader.setBounds(field, &shape.Minimum, &shape.Maximum, &shape.Closed)
+	case *Cone:
+		err = loader.setBounds(field, &shape.Minimum, &shape.Maximum, &shape.Closed)
+	default:
+		err = loader.errorf(field.key, "only cylinders and cones have %s", field.key.Value)
+	}
+
+	return err
+}
+
+func (loader *loader) setBounds(field field, minimum *float64, maximum *float64, closed *bool) error {
+	var err error
+
+	switch field.key.Value {
+	case "minimum":
+		*minimum, err = loader.float(field.value)
+	case "maximum":
+		*maximum, err = loader.float(field.value)
+	case "closed":
+		*closed, err = loader.bool(field.value)
+	}
+
+	return err
+}
+
+func withoutKey(node *yaml.Node, key string) *yaml.Node {
+	result := *node
+	result.Content = []*yaml.Node{}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != key {
+			result.Content = append(result.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+
+	return &result
+}